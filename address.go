@@ -1,7 +1,6 @@
 package onfido
 
 import (
-	"encoding/json"
 	"errors"
 	"net/url"
 )
@@ -33,43 +32,28 @@ type Address struct {
 	EndDate   string `json:"end_date,omitempty"`
 }
 
-// PickerIter represents an address picker iterator
+// PickerIter represents an address picker iterator.
+// It only adds the deprecated Address accessor to Iter[*Address].
 type PickerIter struct {
-	*iter
+	*Iter[*Address]
 }
 
 // Address returns the current address on the iterator.
+//
+// Deprecated: use Current.
 func (i *PickerIter) Address() *Address {
-	return i.Current().(*Address)
+	return i.Current()
 }
 
 // PickAddresses retrieves the list of addresses matched against the provided postcode.
 // see https://documentation.onfido.com/?shell#address-picker
-func (c *Client) PickAddresses(postcode string) *PickerIter {
+func (c *Client) PickAddresses(postcode string, opts ...ListOption) *PickerIter {
 	if postcode == "" {
-		return &PickerIter{&iter{
-			err: ErrEmptyPostcode,
-		}}
-	}
-	handler := func(body []byte) ([]interface{}, error) {
-		var a Addresses
-		if err := json.Unmarshal(body, &a); err != nil {
-			return nil, err
-		}
-
-		values := make([]interface{}, len(a.Addresses))
-		for i, v := range a.Addresses {
-			values[i] = v
-		}
-		return values, nil
+		return &PickerIter{errIter[*Address](ErrEmptyPostcode)}
 	}
 
 	params := make(url.Values)
 	params.Set("postcode", postcode)
 
-	return &PickerIter{&iter{
-		c:       c,
-		nextURL: "addresses/pick?" + params.Encode(),
-		handler: handler,
-	}}
+	return &PickerIter{newIter[*Address](c, "PickAddresses", "addresses", listURL("addresses/pick", params, opts))}
 }
@@ -85,7 +85,10 @@ func (c *Client) CreateApplicant(ctx context.Context, a Applicant) (*Applicant,
 	}
 
 	var resp Applicant
-	_, err = c.do(ctx, req, &resp)
+	_, err = c.do(withOperation(ctx, "CreateApplicant"), req, &resp)
+	if err == nil {
+		err = c.checkSandbox("applicant", resp.ID, resp.Sandbox)
+	}
 	return &resp, err
 }
 
@@ -96,7 +99,7 @@ func (c *Client) DeleteApplicant(ctx context.Context, id string) error {
 	if err != nil {
 		return err
 	}
-	_, err = c.do(ctx, req, nil)
+	_, err = c.do(withOperation(ctx, "DeleteApplicant"), req, nil)
 	return err
 }
 
@@ -109,41 +112,34 @@ func (c *Client) GetApplicant(ctx context.Context, id string) (*Applicant, error
 	}
 
 	var resp Applicant
-	_, err = c.do(ctx, req, &resp)
+	_, err = c.do(withOperation(ctx, "GetApplicant"), req, &resp)
+	if err == nil {
+		err = c.checkSandbox("applicant", resp.ID, resp.Sandbox)
+	}
 	return &resp, err
 }
 
-// ApplicantIter represents an applicant iterator
+// ApplicantIter represents an applicant iterator.
+// It only adds the deprecated Applicant accessor to Iter[*Applicant].
 type ApplicantIter struct {
-	*iter
+	*Iter[*Applicant]
 }
 
 // Applicant returns the current applicant on the iterator.
+//
+// Deprecated: use Current.
 func (i *ApplicantIter) Applicant() *Applicant {
-	return i.Current().(*Applicant)
+	return i.Current()
 }
 
 // ListApplicants retrieves the list of applicants.
 // see https://documentation.onfido.com/?shell#list-applicants
-func (c *Client) ListApplicants() *ApplicantIter {
-	handler := func(body []byte) ([]interface{}, error) {
-		var a Applicants
-		if err := json.Unmarshal(body, &a); err != nil {
-			return nil, err
-		}
-
-		values := make([]interface{}, len(a.Applicants))
-		for i, v := range a.Applicants {
-			values[i] = v
-		}
-		return values, nil
+func (c *Client) ListApplicants(opts ...ListOption) *ApplicantIter {
+	it := newIter[*Applicant](c, "ListApplicants", "applicants", listURL("/applicants", nil, opts))
+	it.validate = func(a *Applicant) error {
+		return c.checkSandbox("applicant", a.ID, a.Sandbox)
 	}
-
-	return &ApplicantIter{&iter{
-		c:       c,
-		nextURL: "/applicants",
-		handler: handler,
-	}}
+	return &ApplicantIter{it}
 }
 
 // UpdateApplicant updates an applicant by its id.
@@ -163,6 +159,9 @@ func (c *Client) UpdateApplicant(ctx context.Context, a Applicant) (*Applicant,
 	}
 
 	var resp Applicant
-	_, err = c.do(ctx, req, &resp)
+	_, err = c.do(withOperation(ctx, "UpdateApplicant"), req, &resp)
+	if err == nil {
+		err = c.checkSandbox("applicant", resp.ID, resp.Sandbox)
+	}
 	return &resp, err
 }
@@ -21,6 +21,14 @@ type Breakdowns map[string]Breakdown
 type Breakdown struct {
 	Result        *BreakdownResult `json:"result"`
 	SubBreakdowns SubBreakdowns    `json:"breakdown"`
+	Properties    Properties       `json:"properties,omitempty"`
+}
+
+// TypedBreakdown is a breakdown whose sub-breakdowns are modelled by S, see Report.AsDocument.
+type TypedBreakdown[S any] struct {
+	Result        *BreakdownResult `json:"result"`
+	SubBreakdowns S                `json:"breakdown"`
+	Properties    Properties       `json:"properties,omitempty"`
 }
 
 type SubBreakdowns map[string]SubBreakdown
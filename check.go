@@ -4,6 +4,12 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sort"
+	"sync"
 	"time"
 )
 
@@ -41,6 +47,7 @@ type CheckRequest struct {
 	ReportConfiguration   map[string]interface{} `json:"report_configuration,omitempty"`
 	// Consider is used for Sandbox Testing of multiple report scenarios.
 	// see https://documentation.onfido.com/#sandbox-responses
+	// CreateCheck fails with ErrSandboxOnly if it is set with a live token.
 	Consider []string `json:"consider,omitempty"`
 }
 
@@ -77,6 +84,9 @@ type Checks struct {
 // CreateCheck creates a new check for the provided applicant.
 // see https://documentation.onfido.com/?shell#create-check
 func (c *Client) CreateCheck(ctx context.Context, cr CheckRequest) (*Check, error) {
+	if err := c.checkSandboxOnly("consider", len(cr.Consider) > 0); err != nil {
+		return nil, err
+	}
 	jsonStr, err := json.Marshal(cr)
 	if err != nil {
 		return nil, err
@@ -88,7 +98,10 @@ func (c *Client) CreateCheck(ctx context.Context, cr CheckRequest) (*Check, erro
 	}
 
 	var resp Check
-	_, err = c.do(ctx, req, &resp)
+	_, err = c.do(withOperation(ctx, "CreateCheck"), req, &resp)
+	if err == nil {
+		err = c.checkSandbox("check", resp.ID, resp.Sandbox)
+	}
 	return &resp, err
 }
 
@@ -101,37 +114,133 @@ func (c *Client) GetCheck(ctx context.Context, id string) (*Check, error) {
 	}
 
 	var resp Check
-	_, err = c.do(ctx, req, &resp)
+	_, err = c.do(withOperation(ctx, "GetCheck"), req, &resp)
+	if err == nil {
+		err = c.checkSandbox("check", resp.ID, resp.Sandbox)
+	}
 	return &resp, err
 }
 
+// DefaultExpandWorkers is the number of reports GetCheckExpanded fetches concurrently by default
+const DefaultExpandWorkers = 4
+
+// ExpandOption configures how GetCheckExpanded fetches the reports of a check.
+type ExpandOption func(*expandConfig)
+
+type expandConfig struct {
+	workers int
+	list    bool
+}
+
+// ExpandWorkers sets the number of reports fetched concurrently, DefaultExpandWorkers by default.
+func ExpandWorkers(n int) ExpandOption {
+	return func(cfg *expandConfig) {
+		cfg.workers = n
+	}
+}
+
+// ExpandWithList fetches the reports with a single ListReports call instead of
+// a GetReport call per report, which is cheaper for checks with many reports.
+func ExpandWithList() ExpandOption {
+	return func(cfg *expandConfig) {
+		cfg.list = true
+	}
+}
+
 // GetCheckExpanded retrieves a check by its ID, with
 // the Check's Reports expanded within the returned CheckExpanded object.
+// The reports are fetched concurrently and kept in the order of the check's ReportIDs,
+// the errors of all the failed fetches being joined in the returned error.
 // see https://documentation.onfido.com/?shell#retrieve-check (Shell) but refer to the JSON
 // response object for https://documentation.onfido.com/?php#check-object (PHP) for the expanded contents.
-func (c *Client) GetCheckExpanded(ctx context.Context, id string) (*CheckExpanded, error) {
+func (c *Client) GetCheckExpanded(ctx context.Context, id string, opts ...ExpandOption) (*CheckExpanded, error) {
 	// Get the Check object. This only includes Report IDs, not the expanded Report objects.
 	check, err := c.GetCheck(ctx, id)
 	if err != nil {
 		return nil, err
 	}
+	return c.expandCheck(ctx, check, opts)
+}
 
-	// Create the expanded check with the base check data
-	expanded := &CheckExpanded{
-		Check:   *check,
-		Reports: make([]*Report, len(check.ReportIDs)),
+// expandCheck fetches the reports of the check.
+func (c *Client) expandCheck(ctx context.Context, check *Check, opts []ExpandOption) (*CheckExpanded, error) {
+	cfg := expandConfig{workers: DefaultExpandWorkers}
+	for _, opt := range opts {
+		opt(&cfg)
 	}
 
-	// For each Report ID in the Check object, fetch (expand) the Report
-	for i, reportID := range check.ReportIDs {
-		rep, err := c.GetReport(ctx, reportID)
-		if err != nil {
-			return nil, err
-		}
-		expanded.Reports[i] = rep
+	var (
+		reports []*Report
+		err     error
+	)
+	if cfg.list {
+		reports, err = c.listCheckReports(ctx, check)
+	} else {
+		reports, err = c.getReports(ctx, check.ReportIDs, cfg.workers)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &CheckExpanded{Check: *check, Reports: reports}, nil
+}
+
+// getReports fetches the reports by their IDs with up to workers concurrent requests.
+func (c *Client) getReports(ctx context.Context, ids []string, workers int) ([]*Report, error) {
+	if workers < 1 {
+		workers = 1
+	}
+	reports := make([]*Report, len(ids))
+	errs := make([]error, len(ids))
+	sem := make(chan struct{}, workers)
+
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, id string) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			rep, err := c.GetReport(ctx, id)
+			if err != nil {
+				errs[i] = fmt.Errorf("report %s: %w", id, err)
+				return
+			}
+			reports[i] = rep
+		}(i, id)
 	}
+	wg.Wait()
 
-	return expanded, nil
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+	return reports, nil
+}
+
+// listCheckReports lists the reports of the check, ordering them as its ReportIDs.
+func (c *Client) listCheckReports(ctx context.Context, check *Check) ([]*Report, error) {
+	reports, err := c.ListReports(check.ID).Collect(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	order := make(map[string]int, len(check.ReportIDs))
+	for i, id := range check.ReportIDs {
+		order[id] = i
+	}
+	sort.SliceStable(reports, func(i, j int) bool {
+		oi, ok := order[reports[i].ID]
+		if !ok {
+			oi = len(order)
+		}
+		oj, ok := order[reports[j].ID]
+		if !ok {
+			oj = len(order)
+		}
+		return oi < oj
+	})
+	return reports, nil
 }
 
 // ResumeCheck resumes a paused check by its ID.
@@ -143,52 +252,53 @@ func (c *Client) ResumeCheck(ctx context.Context, id string) (*Check, error) {
 	}
 
 	var resp Check
-	_, err = c.do(ctx, req, &resp)
+	_, err = c.do(withOperation(ctx, "ResumeCheck"), req, &resp)
+	if err == nil {
+		err = c.checkSandbox("check", resp.ID, resp.Sandbox)
+	}
 	return &resp, err
 }
 
 // DownloadCheck downloads a PDF summary of a check by its ID.
 // see https://documentation.onfido.com/api/latest/#download-check
 func (c *Client) DownloadCheck(ctx context.Context, id string) ([]byte, error) {
-	req, err := c.newRequest("GET", "/checks/"+id+"/download", nil)
-	if err != nil {
-		return nil, err
-	}
-
 	var buf bytes.Buffer
-	_, err = c.do(ctx, req, &buf)
+	err := c.DownloadCheckTo(ctx, id, &buf)
 	return buf.Bytes(), err
 }
 
-// CheckIter represents a check iterator
+// DownloadCheckTo downloads a PDF summary of a check by its ID, copying it to w
+// as it is received.
+func (c *Client) DownloadCheckTo(ctx context.Context, id string, w io.Writer) error {
+	return c.downloadTo(withOperation(ctx, "DownloadCheck"), "/checks/"+id+"/download", w)
+}
+
+// DownloadCheckStream downloads a PDF summary of a check by its ID, returning
+// the response body for the caller to read and close.
+func (c *Client) DownloadCheckStream(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.downloadStream(withOperation(ctx, "DownloadCheck"), "/checks/"+id+"/download")
+}
+
+// CheckIter represents a check iterator.
+// It only adds the deprecated Check accessor to Iter[*Check].
 type CheckIter struct {
-	*iter
+	*Iter[*Check]
 }
 
 // Check returns the current item in the iterator as a Check.
+//
+// Deprecated: use Current.
 func (i *CheckIter) Check() *Check {
-	return i.Current().(*Check)
+	return i.Current()
 }
 
 // ListChecks retrieves the list of checks for the provided applicant.
 // see https://documentation.onfido.com/?shell#list-checks
-func (c *Client) ListChecks(applicantID string) *CheckIter {
-	handler := func(body []byte) ([]interface{}, error) {
-		var r Checks
-		if err := json.Unmarshal(body, &r); err != nil {
-			return nil, err
-		}
-
-		values := make([]interface{}, len(r.Checks))
-		for i, v := range r.Checks {
-			values[i] = v
-		}
-		return values, nil
+func (c *Client) ListChecks(applicantID string, opts ...ListOption) *CheckIter {
+	params := url.Values{"applicant_id": {applicantID}}
+	it := newIter[*Check](c, "ListChecks", "checks", listURL("/checks", params, opts))
+	it.validate = func(check *Check) error {
+		return c.checkSandbox("check", check.ID, check.Sandbox)
 	}
-
-	return &CheckIter{&iter{
-		c:       c,
-		nextURL: "/checks?applicant_id=" + applicantID,
-		handler: handler,
-	}}
+	return &CheckIter{it}
 }
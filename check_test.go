@@ -1,11 +1,18 @@
 package onfido_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/stretchr/testify/assert"
@@ -546,3 +553,126 @@ func TestListChecks_ChecksRetrieved(t *testing.T) {
 		t.Fatal(it.Err())
 	}
 }
+
+func TestDownloadCheck(t *testing.T) {
+	content := []byte("%PDF-1.4 check")
+	m := mux.NewRouter()
+	m.HandleFunc("/checks/{id}/download", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, wErr := w.Write(content)
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	data, err := client.DownloadCheck(ctx, "123")
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	var buf bytes.Buffer
+	assert.NoError(t, client.DownloadCheckTo(ctx, "123", &buf))
+	assert.Equal(t, content, buf.Bytes())
+
+	rc, err := client.DownloadCheckStream(ctx, "123")
+	if assert.NoError(t, err) {
+		data, err = ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.Equal(t, content, data)
+		assert.NoError(t, rc.Close())
+	}
+}
+
+// newExpandServer serves a check with the given reports, failing the reports whose ID starts with `fail`.
+// It reports the maximum number of concurrent report fetches.
+func newExpandServer(t *testing.T, ids []string) (*httptest.Server, *int32) {
+	var inFlight, maxInFlight int32
+	var mu sync.Mutex
+	m := mux.NewRouter()
+	m.HandleFunc("/checks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		check, err := json.Marshal(onfido.Check{ID: mux.Vars(r)["id"], ReportIDs: ids})
+		assert.NoError(t, err)
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write(check)
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/reports/{id}", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		mu.Lock()
+		if n > maxInFlight {
+			maxInFlight = n
+		}
+		mu.Unlock()
+		// give the other fetches a chance to start
+		time.Sleep(10 * time.Millisecond)
+
+		id := mux.Vars(r)["id"]
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasPrefix(id, "fail") {
+			w.WriteHeader(http.StatusNotFound)
+			_, wErr := w.Write([]byte(`{"error":{"type":"resource_not_found","message":"not found"}}`))
+			assert.NoError(t, wErr)
+			return
+		}
+		_, wErr := w.Write([]byte(`{"id":"` + id + `"}`))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/reports", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "c1", r.URL.Query().Get("check_id"))
+		// listed in reverse order
+		var reports []string
+		for i := len(ids) - 1; i >= 0; i-- {
+			reports = append(reports, `{"id":"`+ids[i]+`"}`)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"reports":[` + strings.Join(reports, ",") + `]}`))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	return httptest.NewServer(m), &maxInFlight
+}
+
+func reportIDs(reports []*onfido.Report) []string {
+	ids := make([]string, len(reports))
+	for i, r := range reports {
+		ids[i] = r.ID
+	}
+	return ids
+}
+
+func TestGetCheckExpanded_Concurrent(t *testing.T) {
+	ids := []string{"r1", "r2", "r3", "r4", "r5", "r6"}
+	srv, maxInFlight := newExpandServer(t, ids)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	c, err := client.GetCheckExpanded(context.Background(), "c1", onfido.ExpandWorkers(2))
+	assert.NoError(t, err)
+	assert.Equal(t, ids, reportIDs(c.Reports))
+	assert.Equal(t, int32(2), atomic.LoadInt32(maxInFlight))
+}
+
+func TestGetCheckExpanded_JoinsErrors(t *testing.T) {
+	srv, _ := newExpandServer(t, []string{"r1", "fail1", "r2", "fail2"})
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	c, err := client.GetCheckExpanded(context.Background(), "c1")
+	assert.Nil(t, c)
+	assert.True(t, errors.Is(err, onfido.ErrNotFound))
+	assert.EqualError(t, err, "report fail1: not found\nreport fail2: not found")
+}
+
+func TestGetCheckExpanded_WithList(t *testing.T) {
+	ids := []string{"r1", "r2", "r3"}
+	srv, maxInFlight := newExpandServer(t, ids)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	c, err := client.GetCheckExpanded(context.Background(), "c1", onfido.ExpandWithList())
+	assert.NoError(t, err)
+	assert.Equal(t, ids, reportIDs(c.Reports))
+	assert.Equal(t, int32(0), atomic.LoadInt32(maxInFlight))
+}
@@ -0,0 +1,156 @@
+package onfido
+
+import "sort"
+
+// ConsiderReason is a breakdown or sub-breakdown of a report whose result isn't clear.
+type ConsiderReason struct {
+	ReportID   string
+	ReportName ReportName
+	// Path locates the breakdown in the report, e.g. `visual_authenticity.original_document_present`
+	Path string
+	// Result is the breakdown result, e.g. `consider` or `unidentified`
+	Result     string
+	Properties Properties
+	// Explanation describes the reason in English, it is empty for unknown breakdowns.
+	Explanation string
+}
+
+// ConsiderReasons walks the breakdown tree of the report and returns the breakdowns
+// whose result isn't clear, sorted by path. A breakdown is only returned itself when
+// none of its sub-breakdowns explains its result.
+func (r *Report) ConsiderReasons() []ConsiderReason {
+	names := make([]string, 0, len(r.Breakdown))
+	for name := range r.Breakdown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var reasons []ConsiderReason
+	for _, name := range names {
+		b := r.Breakdown[name]
+
+		subNames := make([]string, 0, len(b.SubBreakdowns))
+		for subName := range b.SubBreakdowns {
+			subNames = append(subNames, subName)
+		}
+		sort.Strings(subNames)
+
+		found := false
+		for _, subName := range subNames {
+			sub := b.SubBreakdowns[subName]
+			if sub.Result == nil || *sub.Result == SubBreakdownClear {
+				continue
+			}
+			found = true
+			reasons = append(reasons, r.considerReason(name+"."+subName, string(*sub.Result), sub.Properties))
+		}
+		if !found && b.Result != nil && *b.Result != BreakdownClear {
+			reasons = append(reasons, r.considerReason(name, string(*b.Result), b.Properties))
+		}
+	}
+	return reasons
+}
+
+func (r *Report) considerReason(path, result string, properties Properties) ConsiderReason {
+	return ConsiderReason{
+		ReportID:    r.ID,
+		ReportName:  r.Name,
+		Path:        path,
+		Result:      result,
+		Properties:  properties,
+		Explanation: considerExplanations[path],
+	}
+}
+
+// ConsiderReasons returns the consider reasons of all the reports of the check, in the order of the reports.
+func (c *CheckExpanded) ConsiderReasons() []ConsiderReason {
+	var reasons []ConsiderReason
+	for _, r := range c.Reports {
+		if r != nil {
+			reasons = append(reasons, r.ConsiderReasons()...)
+		}
+	}
+	return reasons
+}
+
+// considerExplanations describes the well known breakdowns and sub-breakdowns.
+var considerExplanations = map[string]string{
+	// document reports
+	"data_comparison":                                "The data on the document doesn't match the applicant details",
+	"data_comparison.first_name":                     "The first name on the document doesn't match the applicant's",
+	"data_comparison.last_name":                      "The last name on the document doesn't match the applicant's",
+	"data_comparison.date_of_birth":                  "The date of birth on the document doesn't match the applicant's",
+	"data_comparison.gender":                         "The gender on the document doesn't match the applicant's",
+	"data_comparison.issuing_country":                "The issuing country doesn't match the one provided",
+	"data_comparison.document_type":                  "The document type doesn't match the one provided",
+	"data_comparison.document_numbers":               "The document numbers don't match the ones provided",
+	"data_comparison.date_of_expiry":                 "The expiry date doesn't match the one provided",
+	"data_comparison.address":                        "The address on the document doesn't match the applicant's",
+	"data_validation":                                "The data on the document failed validation",
+	"data_validation.document_expiration":            "The document has expired",
+	"data_validation.expiry_date":                    "The expiry date on the document isn't valid",
+	"data_validation.date_of_birth":                  "The date of birth on the document isn't valid",
+	"data_validation.gender":                         "The gender on the document isn't valid",
+	"data_validation.mrz":                            "The machine readable zone of the document isn't valid",
+	"data_validation.document_numbers":               "The document numbers aren't valid",
+	"data_validation.barcode":                        "The barcode of the document isn't valid",
+	"data_consistency":                               "The data isn't consistent across the document",
+	"data_consistency.first_name":                    "The first name isn't consistent across the document",
+	"data_consistency.last_name":                     "The last name isn't consistent across the document",
+	"data_consistency.date_of_birth":                 "The date of birth isn't consistent across the document",
+	"data_consistency.date_of_expiry":                "The expiry date isn't consistent across the document",
+	"data_consistency.document_numbers":              "The document numbers aren't consistent across the document",
+	"data_consistency.document_type":                 "The document type isn't consistent across the document",
+	"data_consistency.gender":                        "The gender isn't consistent across the document",
+	"data_consistency.issuing_country":               "The issuing country isn't consistent across the document",
+	"data_consistency.nationality":                   "The nationality isn't consistent across the document",
+	"data_consistency.multiple_data_sources_present": "The document doesn't have several data sources to compare",
+	"image_integrity":                                "The image couldn't be processed",
+	"image_integrity.image_quality":                  "The image quality is too low, e.g. blurred, obscured or cut off",
+	"image_integrity.supported_document":             "The document isn't supported",
+	"image_integrity.colour_picture":                 "The image isn't in colour",
+	"image_integrity.conclusive_document_quality":    "The document couldn't be verified with confidence, e.g. it is damaged or a digital copy",
+	"image_integrity.face_detected":                  "No face could be found in the image",
+	"image_integrity.source_integrity":               "The image may have been tampered with or not come from a live capture",
+	"visual_authenticity":                            "The document shows signs of being fraudulent",
+	"visual_authenticity.fonts":                      "The fonts on the document don't match the expected ones",
+	"visual_authenticity.picture_face_integrity":     "The face picture on the document may have been tampered with",
+	"visual_authenticity.template":                   "The document doesn't match the expected template",
+	"visual_authenticity.security_features":          "The security features of the document are missing or wrong",
+	"visual_authenticity.original_document_present":  "The document may not be the original, e.g. a photo of a screen or a photocopy",
+	"visual_authenticity.digital_tampering":          "The image shows signs of digital tampering",
+	"visual_authenticity.face_detection":             "No face could be found on the document",
+	"visual_authenticity.other":                      "The document shows other signs of being fraudulent",
+	"visual_authenticity.liveness_detected":          "The applicant may not have been physically present",
+	"visual_authenticity.spoofing_detection":         "The applicant may have presented a photo, screen or mask",
+	"police_record":                                  "The document is recorded as lost or stolen",
+	"compromised_document":                           "The document is known to have been compromised",
+	"age_validation":                                 "The applicant is younger than the minimum accepted age",
+	"age_validation.minimum_accepted_age":            "The applicant is younger than the minimum accepted age",
+	"issuing_authority":                              "The document chip couldn't be verified",
+	"issuing_authority.nfc_active_authentication":    "The document chip failed active authentication",
+	"issuing_authority.nfc_passive_authentication":   "The document chip failed passive authentication",
+
+	// facial similarity reports
+	"face_comparison":            "The applicant's face doesn't match the one on the document",
+	"face_comparison.face_match": "The applicant's face doesn't match the one on the document",
+
+	// watchlist reports
+	"sanction":                      "The applicant matches a sanctions list",
+	"politically_exposed_person":    "The applicant matches a politically exposed persons list",
+	"legal_and_regulatory_warnings": "The applicant matches a legal or regulatory warnings list",
+	"adverse_media":                 "The applicant appears in adverse media",
+	"monitored_lists":               "The applicant matches a monitored list",
+
+	// proof of address reports
+	"document_classification":                    "The proof of address document couldn't be classified",
+	"document_classification.issue_date":         "The proof of address document was issued too long ago",
+	"document_classification.summary_period":     "The summary period of the proof of address document isn't valid",
+	"document_classification.supported_document": "The proof of address document isn't supported",
+
+	// device intelligence reports
+	"device":                          "The device used by the applicant is suspicious",
+	"device.application_authenticity": "The application used by the applicant may have been tampered with",
+	"device.device_integrity":         "The device may be emulated, rooted or otherwise compromised",
+	"device.device_reputation":        "The device has been used for suspicious activity",
+}
@@ -0,0 +1,80 @@
+package onfido_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	onfido "github.com/uw-labs/go-onfido"
+)
+
+func TestReport_ConsiderReasons(t *testing.T) {
+	r := decodeReport(t, `{
+		"id": "r1",
+		"name": "document",
+		"result": "consider",
+		"breakdown": {
+			"visual_authenticity": {"result": "consider", "breakdown": {
+				"original_document_present": {"result": "consider", "properties": {"photo_of_screen": "consider"}},
+				"fonts": {"result": "clear"},
+				"template": {"result": null}
+			}},
+			"police_record": {"result": "consider"},
+			"image_integrity": {"result": "clear", "breakdown": {"image_quality": {"result": "clear"}}},
+			"data_validation": {"result": "consider", "breakdown": {
+				"mrz": {"result": "consider"},
+				"document_expiration": {"result": "consider"}
+			}},
+			"custom_check": {"result": "unidentified", "breakdown": {}}
+		}
+	}`)
+
+	reasons := r.ConsiderReasons()
+	var paths []string
+	for _, reason := range reasons {
+		paths = append(paths, reason.Path)
+		assert.Equal(t, "r1", reason.ReportID)
+		assert.Equal(t, onfido.ReportNameDocument, reason.ReportName)
+	}
+	assert.Equal(t, []string{
+		"custom_check",
+		"data_validation.document_expiration",
+		"data_validation.mrz",
+		"police_record",
+		"visual_authenticity.original_document_present",
+	}, paths)
+
+	assert.Equal(t, "unidentified", reasons[0].Result)
+	assert.Empty(t, reasons[0].Explanation)
+	assert.Equal(t, "The document has expired", reasons[1].Explanation)
+	assert.Equal(t, onfido.Properties{"photo_of_screen": "consider"}, reasons[4].Properties)
+	assert.Equal(t, "The document may not be the original, e.g. a photo of a screen or a photocopy", reasons[4].Explanation)
+}
+
+func TestCheckExpanded_ConsiderReasons(t *testing.T) {
+	c := onfido.CheckExpanded{Reports: []*onfido.Report{
+		decodeReport(t, `{"id": "r1", "name": "document", "result": "clear", "breakdown": {"image_integrity": {"result": "clear"}}}`),
+		decodeReport(t, `{"id": "r2", "name": "watchlist_standard", "breakdown": {"sanction": {"result": "consider"}}}`),
+		decodeReport(t, `{"id": "r3", "name": "facial_similarity_photo", "breakdown": {
+			"face_comparison": {"result": "consider", "breakdown": {"face_match": {"result": "consider", "properties": {"score": 0.2}}}}
+		}}`),
+	}}
+
+	reasons := c.ConsiderReasons()
+	assert.Equal(t, []onfido.ConsiderReason{
+		{
+			ReportID:    "r2",
+			ReportName:  onfido.ReportNameWatchlistStandard,
+			Path:        "sanction",
+			Result:      "consider",
+			Explanation: "The applicant matches a sanctions list",
+		},
+		{
+			ReportID:    "r3",
+			ReportName:  onfido.ReportNameFacialSimilarityPhoto,
+			Path:        "face_comparison.face_match",
+			Result:      "consider",
+			Properties:  onfido.Properties{"score": 0.2},
+			Explanation: "The applicant's face doesn't match the one on the document",
+		},
+	}, reasons)
+}
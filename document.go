@@ -4,12 +4,10 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"mime/multipart"
-	"net/http"
-	"net/textproto"
-	"os"
+	"net/url"
 	"strings"
 	"time"
 )
@@ -24,6 +22,23 @@ const (
 	DocumentTypeTaxID          DocumentType = "tax_id"
 	DocumentTypeVoterID        DocumentType = "voter_id"
 
+	DocumentTypeResidencePermit              DocumentType = "residence_permit"
+	DocumentTypeWorkPermit                   DocumentType = "work_permit"
+	DocumentTypeVisa                         DocumentType = "visa"
+	DocumentTypePostalIdentityCard           DocumentType = "postal_identity_card"
+	DocumentTypeSocialSecurityCard           DocumentType = "social_security_card"
+	DocumentTypeNationalHealthInsuranceCard  DocumentType = "national_health_insurance_card"
+	DocumentTypeBirthCertificate             DocumentType = "birth_certificate"
+	DocumentTypeAsylumRegistrationCard       DocumentType = "asylum_registration_card"
+	DocumentTypeProfessionalIdentityCard     DocumentType = "professional_identification_card"
+	DocumentTypeImmigrationStatusDocument    DocumentType = "immigration_status_document"
+	DocumentTypeVehicleRegistrationCard      DocumentType = "vehicle_registration_card"
+	DocumentTypeBankBuildingSocietyStatement DocumentType = "bank_building_society_statement"
+	DocumentTypeUtilityBill                  DocumentType = "utility_bill"
+	DocumentTypeCouncilTax                   DocumentType = "council_tax"
+	DocumentTypeBenefitLetters               DocumentType = "benefit_letters"
+	DocumentTypeGovernmentLetter             DocumentType = "government_letter"
+
 	DocumentSideFront DocumentSide = "front"
 	DocumentSideBack  DocumentSide = "back"
 )
@@ -34,25 +49,47 @@ type DocumentType string
 // DocumentSide represents a document side (front, back)
 type DocumentSide string
 
+// MaxDocumentSize is the maximum size in bytes of an uploaded document file
+const MaxDocumentSize = 10 << 20
+
+// documentFormat lists the JPG, PNG, PDF and HEIC files accepted for documents.
+var documentFormat = fileFormat{
+	contentTypes: []string{"image/jpeg", "image/png", "application/pdf", "image/heic", "image/heif"},
+	maxSize:      MaxDocumentSize,
+}
+
 // DocumentRequest represents a document request to Onfido API
 type DocumentRequest struct {
-	File        io.ReadSeeker
+	File io.ReadSeeker
+	// FileName is the name the file is uploaded with, taken from File when it's an *os.File.
+	FileName string
+	// ContentType is the content type of the file, sniffed from its content when empty.
+	ContentType string
 	Type        DocumentType
 	Side        DocumentSide
 	ApplicantID string
+	// IssuingCountry is the ISO 3166-1 alpha-3 code of the country which issued the document.
+	IssuingCountry string
+	// ValidateImageQuality makes Onfido reject images of insufficient quality
+	// with an *ImageQualityError, instead of uploading them.
+	ValidateImageQuality bool
+	// Location of the applicant when uploading the document, required for US applicants.
+	Location *Location
 }
 
 // Document represents a document in Onfido API
 type Document struct {
-	ID           string       `json:"id,omitempty"`
-	CreatedAt    *time.Time   `json:"created_at,omitempty"`
-	Href         string       `json:"href,omitempty"`
-	DownloadHref string       `json:"download_href,omitempty"`
-	FileName     string       `json:"file_name,omitempty"`
-	FileType     string       `json:"file_type,omitempty"`
-	FileSize     int          `json:"file_size,omitempty"`
-	Type         DocumentType `json:"type,omitempty"`
-	Side         DocumentSide `json:"side,omitempty"`
+	ID             string       `json:"id,omitempty"`
+	CreatedAt      *time.Time   `json:"created_at,omitempty"`
+	Href           string       `json:"href,omitempty"`
+	DownloadHref   string       `json:"download_href,omitempty"`
+	FileName       string       `json:"file_name,omitempty"`
+	FileType       string       `json:"file_type,omitempty"`
+	FileSize       int          `json:"file_size,omitempty"`
+	Type           DocumentType `json:"type,omitempty"`
+	Side           DocumentSide `json:"side,omitempty"`
+	IssuingCountry string       `json:"issuing_country,omitempty"`
+	ApplicantID    string       `json:"applicant_id,omitempty"`
 }
 
 // Documents represents a list of documents from the Onfido API
@@ -60,75 +97,112 @@ type Documents struct {
 	Documents []*Document `json:"documents"`
 }
 
-var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
-
-func escapeQuotes(s string) string {
-	return quoteEscaper.Replace(s)
-}
-
-// createFormFile creates a new form-data header with the provided field name,
-// file name, and file content type.
-// this is used instead of multipart.Writer.CreateFormFile because Onfido API
-// doesn't accept 'application/octet-stream' as content-type.
-func createFormFile(writer *multipart.Writer, fieldname string, file io.ReadSeeker) (io.Writer, error) {
-	buffer := make([]byte, 512)
-	if _, err := file.Read(buffer); err != nil {
-		return nil, err
-	}
-	if _, err := file.Seek(0, 0); err != nil {
-		return nil, err
-	}
-	var filename string
-	if f, ok := file.(*os.File); ok {
-		filename = f.Name()
-	}
-
-	h := make(textproto.MIMEHeader)
-	h.Set("Content-Disposition",
-		fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
-			escapeQuotes(fieldname), escapeQuotes(filename)))
-	h.Set("Content-Type", http.DetectContentType(buffer))
-
-	return writer.CreatePart(h)
-}
-
 // UploadDocument uploads a document.
+// Files which aren't JPG, PNG, PDF or HEIC, or which are larger than MaxDocumentSize,
+// are rejected with a *FileError before being sent.
 // see https://documentation.onfido.com/?shell#upload-document
 func (c *Client) UploadDocument(ctx context.Context, dr DocumentRequest) (*Document, error) {
-	body := &bytes.Buffer{}
-	writer := multipart.NewWriter(body)
-
-	part, err := createFormFile(writer, "file", dr.File)
+	file, err := newFormFile("file", dr.File, dr.FileName, dr.ContentType)
 	if err != nil {
 		return nil, err
 	}
-	if _, err := io.Copy(part, dr.File); err != nil {
+	if err := file.validate(documentFormat); err != nil {
 		return nil, err
 	}
-	if err := writer.WriteField("type", string(dr.Type)); err != nil {
-		return nil, err
-	}
-	if err := writer.WriteField("side", string(dr.Side)); err != nil {
-		return nil, err
+	fields := []formField{
+		{"type", string(dr.Type)},
+		{"side", string(dr.Side)},
+		{"applicant_id", dr.ApplicantID},
 	}
-	if err := writer.WriteField("applicant_id", dr.ApplicantID); err != nil {
-		return nil, err
+	if dr.IssuingCountry != "" {
+		fields = append(fields, formField{"issuing_country", dr.IssuingCountry})
 	}
-	if err := writer.Close(); err != nil {
-		return nil, err
+	if dr.ValidateImageQuality {
+		fields = append(fields, formField{"validate_image_quality", "true"})
 	}
-
-	req, err := c.newRequest("POST", "/documents", body)
-	req.Header.Set("Content-Type", writer.FormDataContentType())
-	if err != nil {
-		return nil, err
+	if dr.Location != nil {
+		location, err := json.Marshal(dr.Location)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, formField{"location", string(location)})
 	}
 
 	var resp Document
-	_, err = c.do(ctx, req, &resp)
+	err = c.upload(withOperation(ctx, "UploadDocument"), "/documents", file, fields, &resp)
+	if err != nil && dr.ValidateImageQuality {
+		err = imageQualityError(err)
+	}
 	return &resp, err
 }
 
+// ImageQualityCheck names an image quality check of the document upload validation
+type ImageQualityCheck string
+
+// Image quality checks run when DocumentRequest.ValidateImageQuality is set
+const (
+	ImageQualityDocumentDetection ImageQualityCheck = "detect_document"
+	ImageQualityCutoff            ImageQualityCheck = "detect_cutoff"
+	ImageQualityGlare             ImageQualityCheck = "detect_glare"
+	ImageQualityBlur              ImageQualityCheck = "detect_blur"
+)
+
+// ImageQualityFailure is a failed image quality check along with its messages.
+type ImageQualityFailure struct {
+	Check    ImageQualityCheck
+	Messages []string
+}
+
+// ImageQualityError means a document image was rejected by the image quality validation.
+// It unwraps to the *Error returned by Onfido, thus matching ErrValidation.
+type ImageQualityError struct {
+	Failures []ImageQualityFailure
+	Err      *Error
+}
+
+func (e *ImageQualityError) Error() string {
+	checks := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		checks[i] = string(f.Check)
+	}
+	return fmt.Sprintf("document image quality validation failed: %s", strings.Join(checks, ", "))
+}
+
+// Unwrap returns the API error.
+func (e *ImageQualityError) Unwrap() error {
+	return e.Err
+}
+
+// Failed reports whether the given check failed.
+func (e *ImageQualityError) Failed(check ImageQualityCheck) bool {
+	for _, f := range e.Failures {
+		if f.Check == check {
+			return true
+		}
+	}
+	return false
+}
+
+// imageQualityError turns the validation errors of failed image quality checks into an *ImageQualityError.
+func imageQualityError(err error) error {
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || !errors.Is(apiErr, ErrValidation) {
+		return err
+	}
+
+	var failures []ImageQualityFailure
+	for _, f := range apiErr.FieldErrors() {
+		switch check := ImageQualityCheck(f.Path); check {
+		case ImageQualityDocumentDetection, ImageQualityCutoff, ImageQualityGlare, ImageQualityBlur:
+			failures = append(failures, ImageQualityFailure{Check: check, Messages: f.Messages})
+		}
+	}
+	if len(failures) == 0 {
+		return err
+	}
+	return &ImageQualityError{Failures: failures, Err: apiErr}
+}
+
 // GetDocument retrieves a single document by its ID.
 // see https://documentation.onfido.com/?shell#retrieve-document
 func (c *Client) GetDocument(ctx context.Context, id string) (*Document, error) {
@@ -138,52 +212,46 @@ func (c *Client) GetDocument(ctx context.Context, id string) (*Document, error)
 	}
 
 	var resp Document
-	_, err = c.do(ctx, req, &resp)
+	_, err = c.do(withOperation(ctx, "GetDocument"), req, &resp)
 	return &resp, err
 }
 
 // DownloadDocument downloads the file data for a document by its ID.
 // see https://documentation.onfido.com/?shell#download-document
 func (c *Client) DownloadDocument(ctx context.Context, id string) ([]byte, error) {
-	req, err := c.newRequest("GET", "/documents/"+id+"/download", nil)
-	if err != nil {
-		return nil, err
-	}
-
 	var buf bytes.Buffer
-	_, err = c.do(ctx, req, &buf)
+	err := c.DownloadDocumentTo(ctx, id, &buf)
 	return buf.Bytes(), err
 }
 
-// DocumentIter represents a document iterator
+// DownloadDocumentTo downloads the file data for a document by its ID, copying it to w
+// as it is received.
+func (c *Client) DownloadDocumentTo(ctx context.Context, id string, w io.Writer) error {
+	return c.downloadTo(withOperation(ctx, "DownloadDocument"), "/documents/"+id+"/download", w)
+}
+
+// DownloadDocumentStream downloads the file data for a document by its ID, returning
+// the response body for the caller to read and close.
+func (c *Client) DownloadDocumentStream(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.downloadStream(withOperation(ctx, "DownloadDocument"), "/documents/"+id+"/download")
+}
+
+// DocumentIter represents a document iterator.
+// It only adds the deprecated Document accessor to Iter[*Document].
 type DocumentIter struct {
-	*iter
+	*Iter[*Document]
 }
 
 // Document returns the current item in the iterator as a Document.
+//
+// Deprecated: use Current.
 func (i *DocumentIter) Document() *Document {
-	return i.Current().(*Document)
+	return i.Current()
 }
 
 // ListDocuments retrieves the list of documents for the provided applicant.
 // see https://documentation.onfido.com/?shell#list-documents
-func (c *Client) ListDocuments(applicantID string) *DocumentIter {
-	handler := func(body []byte) ([]interface{}, error) {
-		var d Documents
-		if err := json.Unmarshal(body, &d); err != nil {
-			return nil, err
-		}
-
-		values := make([]interface{}, len(d.Documents))
-		for i, v := range d.Documents {
-			values[i] = v
-		}
-		return values, nil
-	}
-
-	return &DocumentIter{&iter{
-		c:       c,
-		nextURL: "/documents?applicant_id=" + applicantID,
-		handler: handler,
-	}}
+func (c *Client) ListDocuments(applicantID string, opts ...ListOption) *DocumentIter {
+	params := url.Values{"applicant_id": {applicantID}}
+	return &DocumentIter{newIter[*Document](c, "ListDocuments", "documents", listURL("/documents", params, opts))}
 }
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -13,6 +15,9 @@ import (
 	onfido "github.com/uw-labs/go-onfido"
 )
 
+// pngFile starts with the PNG signature so that it is accepted as a document
+var pngFile = []byte("\x89PNG\r\n\x1a\ntest")
+
 func TestUploadDocument_NonOKResponse(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusForbidden)
@@ -25,7 +30,7 @@ func TestUploadDocument_NonOKResponse(t *testing.T) {
 	client.Endpoint = srv.URL
 
 	docReq := onfido.DocumentRequest{
-		File:        bytes.NewReader([]byte("test")),
+		File:        bytes.NewReader(pngFile),
 		Type:        onfido.DocumentTypeIDCard,
 		Side:        onfido.DocumentSideFront,
 		ApplicantID: "test-applicant",
@@ -69,7 +74,7 @@ func TestUploadDocument_DocumentUploaded(t *testing.T) {
 	client.Endpoint = srv.URL
 
 	d, err := client.UploadDocument(context.Background(), onfido.DocumentRequest{
-		File:        bytes.NewReader([]byte("test")),
+		File:        bytes.NewReader(pngFile),
 		Type:        expected.Type,
 		Side:        expected.Side,
 		ApplicantID: applicantID,
@@ -222,3 +227,140 @@ func TestListDocuments_DocumentsRetrieved(t *testing.T) {
 		t.Fatal(it.Err())
 	}
 }
+
+func TestDownloadDocument(t *testing.T) {
+	content := []byte("%PDF-1.4 document")
+	m := mux.NewRouter()
+	m.HandleFunc("/documents/{id}/download", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] != "123" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/pdf")
+		_, wErr := w.Write(content)
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	data, err := client.DownloadDocument(ctx, "123")
+	assert.NoError(t, err)
+	assert.Equal(t, content, data)
+
+	var buf bytes.Buffer
+	assert.NoError(t, client.DownloadDocumentTo(ctx, "123", &buf))
+	assert.Equal(t, content, buf.Bytes())
+
+	rc, err := client.DownloadDocumentStream(ctx, "123")
+	if assert.NoError(t, err) {
+		data, err = ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.Equal(t, content, data)
+		assert.NoError(t, rc.Close())
+	}
+
+	_, err = client.DownloadDocumentStream(ctx, "456")
+	assert.True(t, errors.Is(err, onfido.ErrNotFound))
+	assert.True(t, errors.Is(client.DownloadDocumentTo(ctx, "456", ioutil.Discard), onfido.ErrNotFound))
+}
+
+// BenchmarkDownloadDocument compares buffering a large download with copying it as it is received.
+func BenchmarkDownloadDocument(b *testing.B) {
+	content := bytes.Repeat([]byte("0123456789abcdef"), 1<<18) // 4MB
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/pdf")
+		_, _ = w.Write(content)
+	}))
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	b.Run("bytes", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := client.DownloadDocument(ctx, "123"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("writer", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if err := client.DownloadDocumentTo(ctx, "123", ioutil.Discard); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestUploadDocument_AllParameters(t *testing.T) {
+	m := mux.NewRouter()
+	m.HandleFunc("/documents", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "residence_permit", r.FormValue("type"))
+		assert.Equal(t, "FRA", r.FormValue("issuing_country"))
+		assert.Equal(t, "true", r.FormValue("validate_image_quality"))
+		assert.JSONEq(t, `{"ip_address":"127.0.0.1","country_of_residence":"FRA"}`, r.FormValue("location"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123","type":"residence_permit","issuing_country":"FRA","applicant_id":"456"}`))
+		assert.NoError(t, wErr)
+	}).Methods("POST")
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	d, err := client.UploadDocument(context.Background(), onfido.DocumentRequest{
+		File:                 bytes.NewReader(pngFile),
+		Type:                 onfido.DocumentTypeResidencePermit,
+		ApplicantID:          "456",
+		IssuingCountry:       "FRA",
+		ValidateImageQuality: true,
+		Location:             &onfido.Location{IPAddress: "127.0.0.1", CountryOfResidence: "FRA"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "FRA", d.IssuingCountry)
+	assert.Equal(t, "456", d.ApplicantID)
+}
+
+func TestUploadDocument_ImageQualityError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, wErr := w.Write([]byte(`{"error":{"type":"validation_error","message":"There was a validation error on this request",` +
+			`"fields":{"detect_glare":["glare found in image"],"detect_blur":["image too blurry"]}}}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	docReq := onfido.DocumentRequest{
+		File:                 bytes.NewReader(pngFile),
+		Type:                 onfido.DocumentTypePassport,
+		ValidateImageQuality: true,
+	}
+	_, err := client.UploadDocument(context.Background(), docReq)
+
+	var qualityErr *onfido.ImageQualityError
+	if assert.True(t, errors.As(err, &qualityErr)) {
+		assert.Equal(t, []onfido.ImageQualityFailure{
+			{Check: onfido.ImageQualityBlur, Messages: []string{"image too blurry"}},
+			{Check: onfido.ImageQualityGlare, Messages: []string{"glare found in image"}},
+		}, qualityErr.Failures)
+		assert.True(t, qualityErr.Failed(onfido.ImageQualityGlare))
+		assert.False(t, qualityErr.Failed(onfido.ImageQualityCutoff))
+		assert.EqualError(t, err, "document image quality validation failed: detect_blur, detect_glare")
+	}
+	assert.True(t, errors.Is(err, onfido.ErrValidation))
+	var apiErr *onfido.Error
+	assert.True(t, errors.As(err, &apiErr))
+
+	docReq.File = bytes.NewReader(pngFile)
+	docReq.ValidateImageQuality = false
+	_, err = client.UploadDocument(context.Background(), docReq)
+	assert.True(t, errors.As(err, &apiErr))
+	assert.False(t, errors.As(err, &qualityErr))
+}
@@ -0,0 +1,98 @@
+package onfido
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Environment represents the Onfido environment, live or sandbox, a client is meant to work with
+type Environment string
+
+// Supported environments
+const (
+	EnvironmentLive    Environment = "live"
+	EnvironmentSandbox Environment = "sandbox"
+)
+
+// Environment errors
+var (
+	// ErrEnvironmentMismatch means the token or a returned resource belongs to another environment than the expected one
+	ErrEnvironmentMismatch = errors.New("onfido environment mismatch")
+	// ErrSandboxOnly means a request sets fields which are only supported in the sandbox environment
+	ErrSandboxOnly = errors.New("sandbox only field used with a live token")
+)
+
+// WithEnvironment declares the environment the client is meant to work with.
+// Requests are refused with ErrEnvironmentMismatch when the token belongs to
+// another environment, as are applicants and checks, retrieved or listed, whose
+// sandbox flag doesn't match the environment.
+func WithEnvironment(env Environment) Option {
+	return func(c *Client) {
+		c.environment = env
+	}
+}
+
+// Environment returns the environment declared with WithEnvironment, if any.
+func (c *Client) Environment() Environment {
+	return c.environment
+}
+
+// CheckEnvironment verifies that the current token belongs to the declared environment.
+// It succeeds if no environment was declared.
+func (c *Client) CheckEnvironment() error {
+	token, err := c.CurrentToken()
+	if err != nil {
+		return err
+	}
+	return c.checkTokenEnvironment(token)
+}
+
+func (c *Client) checkTokenEnvironment(t Token) error {
+	switch {
+	case c.environment == "":
+		return nil
+	case c.environment != EnvironmentLive && c.environment != EnvironmentSandbox:
+		return fmt.Errorf("unsupported onfido environment `%s`", c.environment)
+	case t.Prod() != (c.environment == EnvironmentLive):
+		return fmt.Errorf("%w: %s token used for the %s environment", ErrEnvironmentMismatch, tokenEnvironment(t), c.environment)
+	}
+	return nil
+}
+
+// checkSandbox verifies that the sandbox flag of a returned resource matches the declared environment.
+func (c *Client) checkSandbox(resource, id string, sandbox bool) error {
+	if c.environment == "" || sandbox == (c.environment == EnvironmentSandbox) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s %s is not from the %s environment", ErrEnvironmentMismatch, resource, id, c.environment)
+}
+
+// checkSandboxOnly rejects the use of sandbox only fields with a live token.
+func (c *Client) checkSandboxOnly(field string, used bool) error {
+	if !used {
+		return nil
+	}
+	token, err := c.CurrentToken()
+	if err != nil {
+		return err
+	}
+	if token.Prod() {
+		return fmt.Errorf("%w: %s", ErrSandboxOnly, field)
+	}
+	return nil
+}
+
+func tokenEnvironment(t Token) Environment {
+	if t.Prod() {
+		return EnvironmentLive
+	}
+	return EnvironmentSandbox
+}
+
+func parseEnvironment(s string) (Environment, error) {
+	switch env := Environment(s); env {
+	case EnvironmentLive, EnvironmentSandbox:
+		return env, nil
+	}
+	return "", fmt.Errorf("unsupported onfido environment `%s`", s)
+}
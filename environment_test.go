@@ -0,0 +1,128 @@
+package onfido
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckEnvironment(t *testing.T) {
+	cases := []struct {
+		token string
+		env   Environment
+		ok    bool
+	}{
+		{"api_live.123", "", true},
+		{"api_sandbox.123", "", true},
+		{"api_live.123", EnvironmentLive, true},
+		{"api_sandbox.123", EnvironmentSandbox, true},
+		{"api_live.123", EnvironmentSandbox, false},
+		{"api_sandbox.123", EnvironmentLive, false},
+	}
+
+	for _, c := range cases {
+		client := NewClient(c.token, WithEnvironment(c.env))
+		err := client.CheckEnvironment()
+		_, reqErr := client.newRequest("GET", "/applicants", nil)
+		if c.ok {
+			assert.NoError(t, err)
+			assert.NoError(t, reqErr)
+		} else {
+			assert.True(t, errors.Is(err, ErrEnvironmentMismatch), "token %s, environment %s", c.token, c.env)
+			assert.True(t, errors.Is(reqErr, ErrEnvironmentMismatch))
+			assert.NotContains(t, err.Error(), c.token)
+		}
+	}
+}
+
+func TestNewClientFromEnv_EnvironmentMismatch(t *testing.T) {
+	os.Setenv(TokenEnv, "api_live.123")
+	os.Setenv(EnvironmentEnv, "sandbox")
+	defer os.Setenv(TokenEnv, "")
+	defer os.Setenv(EnvironmentEnv, "")
+
+	_, err := NewClientFromEnv()
+	assert.True(t, errors.Is(err, ErrEnvironmentMismatch))
+
+	_, err = NewClientFromEnv(WithEnvironment(EnvironmentLive))
+	assert.NoError(t, err)
+}
+
+func TestCreateCheck_ConsiderWithLiveToken(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123","sandbox":true}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	cr := CheckRequest{
+		ApplicantID: "123",
+		ReportNames: []ReportName{ReportNameDocument},
+		Consider:    []string{string(ReportNameDocument)},
+	}
+
+	client := NewClient("api_live.123", WithBaseURL(srv.URL))
+	_, err := client.CreateCheck(context.Background(), cr)
+	assert.True(t, errors.Is(err, ErrSandboxOnly))
+	assert.Equal(t, 0, requests)
+
+	client = NewClient("api_sandbox.123", WithBaseURL(srv.URL))
+	_, err = client.CreateCheck(context.Background(), cr)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, requests)
+}
+
+func TestGetApplicant_SandboxMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123","sandbox":true}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	client := NewClient("api_sandbox.123", WithBaseURL(srv.URL), WithEnvironment(EnvironmentSandbox))
+	_, err := client.GetApplicant(context.Background(), "123")
+	assert.NoError(t, err)
+	_, err = client.GetCheck(context.Background(), "123")
+	assert.NoError(t, err)
+
+	// a live token talking to a sandbox proxy, the resources give the mismatch away
+	client = NewClient("api_live.123", WithBaseURL(srv.URL), WithEnvironment(EnvironmentLive))
+	_, err = client.GetApplicant(context.Background(), "123")
+	assert.True(t, errors.Is(err, ErrEnvironmentMismatch))
+	_, err = client.GetCheck(context.Background(), "123")
+	assert.True(t, errors.Is(err, ErrEnvironmentMismatch))
+}
+
+func TestListApplicants_SandboxMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"applicants":[{"id":"1","sandbox":true}],"checks":[{"id":"2","sandbox":true}]}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	client := NewClient("api_sandbox.123", WithBaseURL(srv.URL), WithEnvironment(EnvironmentSandbox))
+	applicants, err := client.ListApplicants().Collect(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Len(t, applicants, 1)
+	checks, err := client.ListChecks("1").Collect(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Len(t, checks, 1)
+
+	client = NewClient("api_live.123", WithBaseURL(srv.URL), WithEnvironment(EnvironmentLive))
+	applicants, err = client.ListApplicants().Collect(context.Background(), 0)
+	assert.True(t, errors.Is(err, ErrEnvironmentMismatch))
+	assert.Empty(t, applicants)
+	checks, err = client.ListChecks("1").Collect(context.Background(), 0)
+	assert.True(t, errors.Is(err, ErrEnvironmentMismatch))
+	assert.Empty(t, checks)
+}
@@ -0,0 +1,133 @@
+package onfido
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+)
+
+// RequestIDHeader is the response header carrying the Onfido request ID
+const RequestIDHeader = "X-Request-Id"
+
+// Error classes an *Error can be matched against with errors.Is.
+var (
+	// ErrValidation means the request was rejected because some of its fields are invalid
+	ErrValidation = errors.New("validation error")
+	// ErrAuthorization means the API token is missing, invalid or lacks permissions
+	ErrAuthorization = errors.New("authorization error")
+	// ErrNotFound means the requested resource doesn't exist
+	ErrNotFound = errors.New("resource not found")
+	// ErrRateLimited means the request was rejected because of the account rate limit
+	ErrRateLimited = errors.New("rate limited")
+	// ErrConflict means the request conflicts with the current state of the resource
+	ErrConflict = errors.New("conflict")
+	// ErrServer means Onfido failed to process the request
+	ErrServer = errors.New("server error")
+)
+
+// FieldError represents the validation messages of a single request field.
+type FieldError struct {
+	// Path is the location of the field in the request, e.g. `address.postcode` or `id_numbers[0].value`
+	Path     string
+	Messages []string
+}
+
+func (f FieldError) String() string {
+	return fmt.Sprintf("%s: %v", f.Path, f.Messages)
+}
+
+// Is reports whether the error belongs to the class of target,
+// one of ErrValidation, ErrAuthorization, ErrNotFound, ErrRateLimited,
+// ErrConflict or ErrServer.
+func (e *Error) Is(target error) bool {
+	class := e.class()
+	return class != nil && class == target
+}
+
+func (e *Error) class() error {
+	switch e.Err.Type {
+	case "validation_error":
+		return ErrValidation
+	case "authorization_error", "expired_token", "user_authorization_error", "account_disabled":
+		return ErrAuthorization
+	case "resource_not_found":
+		return ErrNotFound
+	case "rate_limit":
+		return ErrRateLimited
+	}
+
+	if e.Resp == nil {
+		return nil
+	}
+	switch code := e.Resp.StatusCode; {
+	case code == http.StatusBadRequest, code == http.StatusUnprocessableEntity:
+		return ErrValidation
+	case code == http.StatusUnauthorized, code == http.StatusForbidden:
+		return ErrAuthorization
+	case code == http.StatusNotFound, code == http.StatusGone:
+		return ErrNotFound
+	case code == http.StatusConflict:
+		return ErrConflict
+	case code == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case code >= 500:
+		return ErrServer
+	}
+	return nil
+}
+
+// FieldErrors returns the validation errors of the response flattened per field.
+func (e *Error) FieldErrors() []FieldError {
+	return e.Err.Fields.Flatten()
+}
+
+// Flatten walks the nested validation errors and returns them as a list
+// of fields with their messages, sorted by path.
+func (f ErrorFields) Flatten() []FieldError {
+	var out []FieldError
+	for name, v := range f {
+		out = flattenField(out, name, v)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Path < out[j].Path
+	})
+	return out
+}
+
+func flattenField(out []FieldError, path string, v interface{}) []FieldError {
+	switch v := v.(type) {
+	case string:
+		return appendMessages(out, path, v)
+	case []string:
+		return appendMessages(out, path, v...)
+	case map[string]interface{}:
+		for name, nested := range v {
+			out = flattenField(out, path+"."+name, nested)
+		}
+	case map[string][]string:
+		for name, msgs := range v {
+			out = appendMessages(out, path+"."+name, msgs...)
+		}
+	case ErrorFields:
+		return flattenField(out, path, map[string]interface{}(v))
+	case []interface{}:
+		var msgs []string
+		for i, item := range v {
+			if msg, ok := item.(string); ok {
+				msgs = append(msgs, msg)
+				continue
+			}
+			out = flattenField(out, fmt.Sprintf("%s[%d]", path, i), item)
+		}
+		out = appendMessages(out, path, msgs...)
+	}
+	return out
+}
+
+func appendMessages(out []FieldError, path string, msgs ...string) []FieldError {
+	if len(msgs) == 0 {
+		return out
+	}
+	return append(out, FieldError{Path: path, Messages: msgs})
+}
@@ -0,0 +1,112 @@
+package onfido
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestError_Is(t *testing.T) {
+	cases := []struct {
+		status   int
+		errType  string
+		expected error
+	}{
+		{http.StatusUnprocessableEntity, "validation_error", ErrValidation},
+		{http.StatusBadRequest, "", ErrValidation},
+		{http.StatusUnauthorized, "authorization_error", ErrAuthorization},
+		{http.StatusForbidden, "", ErrAuthorization},
+		{http.StatusNotFound, "resource_not_found", ErrNotFound},
+		{http.StatusConflict, "", ErrConflict},
+		{http.StatusTooManyRequests, "rate_limit", ErrRateLimited},
+		{http.StatusInternalServerError, "", ErrServer},
+		{http.StatusBadGateway, "", ErrServer},
+	}
+
+	classes := []error{ErrValidation, ErrAuthorization, ErrNotFound, ErrRateLimited, ErrConflict, ErrServer}
+	for _, c := range cases {
+		e := &Error{Resp: &http.Response{StatusCode: c.status}}
+		e.Err.Type = c.errType
+		err := fmt.Errorf("wrapped: %w", e)
+
+		for _, class := range classes {
+			assert.Equal(t, class == c.expected, errors.Is(err, class), "status %d, class %s", c.status, class)
+		}
+
+		var onfidoErr *Error
+		assert.True(t, errors.As(err, &onfidoErr))
+	}
+}
+
+func TestError_IsUnknown(t *testing.T) {
+	err := &Error{}
+	assert.False(t, errors.Is(err, ErrServer))
+	assert.False(t, errors.Is(err, ErrValidation))
+}
+
+func TestErrorFields_Flatten(t *testing.T) {
+	var e Error
+	err := json.Unmarshal([]byte(`{
+		"error": {
+			"type": "validation_error",
+			"message": "There was a validation error on this request",
+			"fields": {
+				"last_name": ["can't be blank", "is too short (minimum is 2 characters)"],
+				"address": {"postcode": ["can't be blank"]},
+				"addresses": [{"street": ["can't be longer than 32 characters"]}, {}],
+				"id_numbers": [{"value": ["is invalid"], "type": ["is not included in the list"]}]
+			}
+		}
+	}`), &e)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, []FieldError{
+		{Path: "address.postcode", Messages: []string{"can't be blank"}},
+		{Path: "addresses[0].street", Messages: []string{"can't be longer than 32 characters"}},
+		{Path: "id_numbers[0].type", Messages: []string{"is not included in the list"}},
+		{Path: "id_numbers[0].value", Messages: []string{"is invalid"}},
+		{Path: "last_name", Messages: []string{"can't be blank", "is too short (minimum is 2 characters)"}},
+	}, e.FieldErrors())
+}
+
+func TestErrorFields_FlattenTypedShapes(t *testing.T) {
+	fields := ErrorFields{
+		"first_name": []string{"can't be blank"},
+		"address":    map[string][]string{"town": {"can't be blank"}},
+	}
+
+	assert.Equal(t, []FieldError{
+		{Path: "address.town", Messages: []string{"can't be blank"}},
+		{Path: "first_name", Messages: []string{"can't be blank"}},
+	}, fields.Flatten())
+}
+
+func TestDo_ErrorRequestID(t *testing.T) {
+	resp := &http.Response{
+		Header:     make(http.Header),
+		StatusCode: http.StatusNotFound,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"error":{"type":"resource_not_found","message":"not found"}}`)),
+	}
+	resp.Header.Set("Content-Type", "application/json")
+	resp.Header.Set(RequestIDHeader, "req-123")
+
+	client := NewClient("123")
+	client.HTTPClient = &stubbedHTTPClient{resp: resp}
+
+	_, err := client.do(context.Background(), &http.Request{}, nil)
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	var onfidoErr *Error
+	if assert.True(t, errors.As(err, &onfidoErr)) {
+		assert.Equal(t, "req-123", onfidoErr.RequestID)
+	}
+}
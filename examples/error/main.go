@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/uw-labs/go-onfido"
@@ -13,8 +14,14 @@ func main() {
 	client := onfido.NewClient("")
 
 	err := client.DeleteApplicant(ctx, "123")
-	onfidoErr, ok := err.(*onfido.Error)
-	if ok {
-		fmt.Printf("got error from onfido api: %s\n", onfidoErr)
+	var onfidoErr *onfido.Error
+	if errors.As(err, &onfidoErr) {
+		fmt.Printf("got error from onfido api: %s (request id %s)\n", onfidoErr, onfidoErr.RequestID)
+		for _, f := range onfidoErr.FieldErrors() {
+			fmt.Printf("  %s\n", f)
+		}
+	}
+	if errors.Is(err, onfido.ErrAuthorization) {
+		fmt.Println("check the onfido token")
 	}
 }
@@ -10,13 +10,10 @@ import (
 func main() {
 	ctx := context.Background()
 
-	client, err := onfido.NewClientFromEnv()
+	client, err := onfido.NewClientFromEnv(onfido.WithEnvironment(onfido.EnvironmentSandbox))
 	if err != nil {
 		panic(err)
 	}
-	if client.Token.Prod() {
-		panic("onfido token is only for production use")
-	}
 
 	applicant, err := client.CreateApplicant(ctx, onfido.Applicant{
 		Email:     "rcrowe@example.co.uk",
@@ -10,20 +10,15 @@ import (
 func main() {
 	ctx := context.Background()
 
-	client, err := onfido.NewClientFromEnv()
+	client, err := onfido.NewClientFromEnv(onfido.WithEnvironment(onfido.EnvironmentSandbox))
 	if err != nil {
 		panic(err)
 	}
-	if client.Token.Prod() {
-		panic("onfido token is only for production use")
-	}
-
-	iter := client.ListApplicants()
 
-	for iter.Next(ctx) {
-		fmt.Printf("%+v\n", iter.Applicant())
-	}
-	if iter.Err() != nil {
-		panic(iter.Err())
+	for applicant, err := range client.ListApplicants().All(ctx) {
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("%+v\n", applicant)
 	}
 }
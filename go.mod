@@ -1,9 +1,15 @@
 module github.com/uw-labs/go-onfido
 
-go 1.12
+go 1.23
 
 require (
 	github.com/gorilla/mux v1.7.3
 	github.com/stretchr/testify v1.3.0
 	github.com/tomnomnom/linkheader v0.0.0-20180905144013-02ca5825eb80
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 )
@@ -0,0 +1,98 @@
+package onfido
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"time"
+)
+
+// MaxIDPhotoSize is the maximum size in bytes of an uploaded ID photo
+const MaxIDPhotoSize = 10 << 20
+
+// idPhotoFormat lists the JPG and PNG files accepted for ID photos.
+var idPhotoFormat = fileFormat{
+	contentTypes: []string{"image/jpeg", "image/png"},
+	maxSize:      MaxIDPhotoSize,
+}
+
+// IDPhotoRequest represents an ID photo upload request to Onfido API
+type IDPhotoRequest struct {
+	File io.ReadSeeker
+	// FileName is the name the file is uploaded with, taken from File when it's an *os.File.
+	FileName string
+	// ContentType is the content type of the file, sniffed from its content when empty.
+	ContentType string
+	ApplicantID string
+}
+
+// IDPhoto represents an ID photo in Onfido API
+type IDPhoto struct {
+	ID           string     `json:"id,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	Href         string     `json:"href,omitempty"`
+	DownloadHref string     `json:"download_href,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	FileType     string     `json:"file_type,omitempty"`
+	FileSize     int32      `json:"file_size,omitempty"`
+}
+
+// UploadIDPhoto uploads an ID photo of the applicant.
+// Files which aren't JPG or PNG, or which are larger than MaxIDPhotoSize,
+// are rejected with a *FileError before being sent.
+// see https://documentation.onfido.com/?shell#upload-id-photo
+func (c *Client) UploadIDPhoto(ctx context.Context, ir IDPhotoRequest) (*IDPhoto, error) {
+	file, err := newFormFile("file", ir.File, ir.FileName, ir.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.validate(idPhotoFormat); err != nil {
+		return nil, err
+	}
+	fields := []formField{{"applicant_id", ir.ApplicantID}}
+
+	var resp IDPhoto
+	err = c.upload(withOperation(ctx, "UploadIDPhoto"), "/id_photos", file, fields, &resp)
+	return &resp, err
+}
+
+// GetIDPhoto retrieves a single ID photo by its ID.
+// see https://documentation.onfido.com/?shell#retrieve-id-photo
+func (c *Client) GetIDPhoto(ctx context.Context, id string) (*IDPhoto, error) {
+	req, err := c.newRequest("GET", "/id_photos/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp IDPhoto
+	_, err = c.do(withOperation(ctx, "GetIDPhoto"), req, &resp)
+	return &resp, err
+}
+
+// DownloadIDPhoto downloads the file data for an ID photo by its ID.
+// see https://documentation.onfido.com/?shell#download-id-photo
+func (c *Client) DownloadIDPhoto(ctx context.Context, id string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := c.DownloadIDPhotoTo(ctx, id, &buf)
+	return buf.Bytes(), err
+}
+
+// DownloadIDPhotoTo downloads the file data for an ID photo by its ID, copying it to w
+// as it is received.
+func (c *Client) DownloadIDPhotoTo(ctx context.Context, id string, w io.Writer) error {
+	return c.downloadTo(withOperation(ctx, "DownloadIDPhoto"), "/id_photos/"+id+"/download", w)
+}
+
+// DownloadIDPhotoStream downloads the file data for an ID photo by its ID, returning
+// the response body for the caller to read and close.
+func (c *Client) DownloadIDPhotoStream(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.downloadStream(withOperation(ctx, "DownloadIDPhoto"), "/id_photos/"+id+"/download")
+}
+
+// ListIDPhotos retrieves the list of ID photos for the provided applicant.
+// see https://documentation.onfido.com/?shell#list-id-photos
+func (c *Client) ListIDPhotos(applicantID string, opts ...ListOption) *Iter[*IDPhoto] {
+	params := url.Values{"applicant_id": {applicantID}}
+	return newIter[*IDPhoto](c, "ListIDPhotos", "id_photos", listURL("/id_photos", params, opts))
+}
@@ -0,0 +1,101 @@
+package onfido_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	onfido "github.com/uw-labs/go-onfido"
+)
+
+func TestUploadIDPhoto(t *testing.T) {
+	m := mux.NewRouter()
+	m.HandleFunc("/id_photos", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "541d040b", r.FormValue("applicant_id"))
+		file, header, err := r.FormFile("file")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "id.png", header.Filename)
+			assert.Equal(t, "image/png", header.Header.Get("Content-Type"))
+			data, err := ioutil.ReadAll(file)
+			assert.NoError(t, err)
+			assert.Equal(t, pngFile, data)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, wErr := w.Write([]byte(`{"id":"123","file_name":"id.png","file_type":"image/png"}`))
+		assert.NoError(t, wErr)
+	}).Methods("POST")
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	p, err := client.UploadIDPhoto(context.Background(), onfido.IDPhotoRequest{
+		File:        bytes.NewReader(pngFile),
+		FileName:    "id.png",
+		ApplicantID: "541d040b",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "123", p.ID)
+
+	_, err = client.UploadIDPhoto(context.Background(), onfido.IDPhotoRequest{
+		File: bytes.NewReader([]byte("%PDF-1.4")),
+	})
+	assert.True(t, errors.Is(err, onfido.ErrUnsupportedFileType))
+}
+
+func TestIDPhotos(t *testing.T) {
+	applicantID := "541d040b-89f8-444b-8921-16b1333bf1c6"
+	photo := `{"id":"7410a943","file_name":"id.png","file_type":"image/png","file_size":1234}`
+
+	m := mux.NewRouter()
+	m.HandleFunc("/id_photos", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, applicantID, r.URL.Query().Get("applicant_id"))
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id_photos":[` + photo + `]}`))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/id_photos/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] != "7410a943" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(photo))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/id_photos/{id}/download", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/png")
+		_, wErr := w.Write(pngFile)
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	photos, err := client.ListIDPhotos(applicantID).Collect(ctx, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, photos, 1) {
+		assert.Equal(t, "7410a943", photos[0].ID)
+	}
+
+	p, err := client.GetIDPhoto(ctx, "7410a943")
+	assert.NoError(t, err)
+	assert.Equal(t, "id.png", p.FileName)
+	assert.Equal(t, int32(1234), p.FileSize)
+
+	_, err = client.GetIDPhoto(ctx, "unknown")
+	assert.True(t, errors.Is(err, onfido.ErrNotFound))
+
+	data, err := client.DownloadIDPhoto(ctx, "7410a943")
+	assert.NoError(t, err)
+	assert.Equal(t, pngFile, data)
+}
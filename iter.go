@@ -0,0 +1,420 @@
+package onfido
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"strconv"
+
+	"github.com/tomnomnom/linkheader"
+)
+
+// TotalCountHeader is the response header holding the number of items of a list
+const TotalCountHeader = "X-Total-Count"
+
+// ListOption configures the pagination and filtering of a list endpoint.
+type ListOption func(url.Values)
+
+// Page starts the listing at page n, pages being numbered from 1.
+func Page(n int) ListOption {
+	return func(v url.Values) {
+		v.Set("page", strconv.Itoa(n))
+	}
+}
+
+// PerPage sets the number of items fetched per page.
+func PerPage(n int) ListOption {
+	return func(v url.Values) {
+		v.Set("per_page", strconv.Itoa(n))
+	}
+}
+
+// IncludeDeleted includes the applicants scheduled for deletion, it only applies to ListApplicants.
+func IncludeDeleted() ListOption {
+	return func(v url.Values) {
+		v.Set("include_deleted", "true")
+	}
+}
+
+// listURL builds the URL of the first page of a list endpoint.
+func listURL(path string, params url.Values, opts []ListOption) string {
+	if params == nil {
+		params = make(url.Values)
+	}
+	for _, opt := range opts {
+		opt(params)
+	}
+	if len(params) == 0 {
+		return path
+	}
+	return path + "?" + params.Encode()
+}
+
+// Iter iterates over the items of a list endpoint, fetching the pages
+// lazily by following the `Link: rel="next"` response header.
+type Iter[T any] struct {
+	c       *Client
+	op      string
+	key     string
+	nextURL string
+	prevURL string
+	lastURL string
+	total   int
+
+	values []T
+	cur    T
+	err    error
+	// validate, when set, checks every fetched item, failing the iteration on error
+	validate func(T) error
+
+	limit int
+	count int
+
+	depth       int
+	pages       chan *page[T]
+	prefetchErr error
+	cancel      context.CancelFunc
+}
+
+// newIter creates an iterator over the items found under key in the pages starting at first.
+func newIter[T any](c *Client, op, key, first string) *Iter[T] {
+	return &Iter[T]{
+		c:       c,
+		op:      op,
+		key:     key,
+		nextURL: first,
+		total:   -1,
+		limit:   -1,
+	}
+}
+
+// errIter creates an iterator failing straight away with err.
+func errIter[T any](err error) *Iter[T] {
+	return &Iter[T]{err: err, total: -1}
+}
+
+// Current returns the item the iterator is positioned on.
+func (it *Iter[T]) Current() T {
+	return it.cur
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (it *Iter[T]) Err() error {
+	return it.err
+}
+
+// Next advances the iterator to the next item, fetching the next page if needed.
+// It returns false once the items are exhausted or an error occurred, see Err.
+func (it *Iter[T]) Next(ctx context.Context) bool {
+	if it.err != nil || it.count == it.limit {
+		it.Close()
+		return false
+	}
+	for len(it.values) == 0 && it.nextURL != "" {
+		var (
+			p   *page[T]
+			err error
+		)
+		if it.depth > 0 {
+			p, err = it.prefetched(ctx)
+		} else {
+			p, err = it.fetch(ctx, it.nextURL)
+		}
+		if err != nil {
+			it.err = err
+			it.Close()
+			return false
+		}
+		it.apply(p)
+	}
+	if len(it.values) == 0 {
+		it.Close()
+		return false
+	}
+
+	it.cur = it.values[0]
+	it.values = it.values[1:]
+	it.count++
+	return true
+}
+
+// page holds a fetched page of items along with its pagination details.
+type page[T any] struct {
+	values []T
+	next   string
+	prev   string
+	last   string
+	total  int
+}
+
+func (it *Iter[T]) fetch(ctx context.Context, pageURL string) (*page[T], error) {
+	req, err := it.c.newRequest("GET", pageURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := it.c.roundTrip(withOperation(ctx, it.op), req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if !isJSONResponse(resp) {
+		return nil, errors.New("non json response")
+	}
+
+	values, err := decodeItems[T](resp.Body, it.key)
+	if err != nil {
+		return nil, err
+	}
+	if it.validate != nil {
+		for _, v := range values {
+			if err := it.validate(v); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	links := linkheader.Parse(resp.Header.Get("Link"))
+	p := &page[T]{
+		values: values,
+		next:   linkURL(links, "next"),
+		prev:   linkURL(links, "prev"),
+		last:   linkURL(links, "last"),
+		total:  -1,
+	}
+	if total, err := strconv.Atoi(resp.Header.Get(TotalCountHeader)); err == nil {
+		p.total = total
+	}
+	return p, nil
+}
+
+// decodeItems decodes the items of the array found under key in the JSON object read from r.
+// The items are decoded one at a time straight from r, the other members of the object are skipped.
+func decodeItems[T any](r io.Reader, key string) ([]T, error) {
+	dec := json.NewDecoder(r)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var values []T
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		if tok != key {
+			if err := skipValue(dec); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if tok, err = dec.Token(); err != nil {
+			return nil, err
+		}
+		if tok == nil {
+			continue
+		}
+		if tok != json.Delim('[') {
+			return nil, fmt.Errorf("unexpected %v for `%s`, expected an array", tok, key)
+		}
+		for dec.More() {
+			var v T
+			if err := dec.Decode(&v); err != nil {
+				return nil, err
+			}
+			values = append(values, v)
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, err
+		}
+	}
+	return values, expectDelim(dec, '}')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != delim {
+		return fmt.Errorf("unexpected %v, expected %v", tok, delim)
+	}
+	return nil
+}
+
+// skipValue reads past the next value of dec without decoding it.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+func (it *Iter[T]) apply(p *page[T]) {
+	it.values = p.values
+	it.nextURL = p.next
+	it.prevURL = p.prev
+	it.lastURL = p.last
+	if p.total >= 0 {
+		it.total = p.total
+	}
+}
+
+// Prefetch makes the iterator fetch up to depth pages ahead in the background
+// while the current one is being consumed. The background fetching starts with
+// the next call to Next and stops when the iteration ends, when the context given
+// to that first call is done, or when Close is called, which must be done when
+// abandoning a Next loop early, All and Collect doing it themselves. The first
+// error encountered is returned by Err once the pages fetched before it have
+// been consumed.
+func (it *Iter[T]) Prefetch(depth int) *Iter[T] {
+	if it.pages == nil && depth > 0 {
+		it.depth = depth
+	}
+	return it
+}
+
+// prefetched returns the next page fetched in the background, starting the
+// background fetching if needed.
+func (it *Iter[T]) prefetched(ctx context.Context) (*page[T], error) {
+	if it.pages == nil {
+		it.startPrefetch(ctx)
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case p, ok := <-it.pages:
+		if !ok {
+			// the fetching goroutine sets prefetchErr before closing the channel
+			if it.prefetchErr != nil {
+				return nil, it.prefetchErr
+			}
+			return &page[T]{total: -1}, nil
+		}
+		return p, nil
+	}
+}
+
+func (it *Iter[T]) startPrefetch(ctx context.Context) {
+	ctx, it.cancel = context.WithCancel(ctx)
+	// the goroutine holds a page while waiting to hand it over, so buffer one less than depth
+	pages := make(chan *page[T], it.depth-1)
+	it.pages = pages
+
+	go func(pageURL string) {
+		defer close(pages)
+		for pageURL != "" {
+			p, err := it.fetch(ctx, pageURL)
+			if err != nil {
+				it.prefetchErr = err
+				return
+			}
+			select {
+			case pages <- p:
+			case <-ctx.Done():
+				it.prefetchErr = ctx.Err()
+				return
+			}
+			pageURL = p.next
+		}
+	}(it.nextURL)
+}
+
+// Close stops the background fetching started by Prefetch. Iterators
+// which don't prefetch don't need to be closed.
+func (it *Iter[T]) Close() {
+	if it.cancel != nil {
+		it.cancel()
+	}
+}
+
+func linkURL(links linkheader.Links, rel string) string {
+	if l := links.FilterByRel(rel); len(l) > 0 {
+		return l[0].URL
+	}
+	return ""
+}
+
+// TotalCount returns the total number of items of the list, as reported by
+// the `X-Total-Count` header of the last page fetched. It returns false until
+// a page is fetched or if the endpoint doesn't report it.
+func (it *Iter[T]) TotalCount() (int, bool) {
+	return it.total, it.total >= 0
+}
+
+// NextURL returns the URL of the next page to be fetched, if any.
+func (it *Iter[T]) NextURL() string {
+	return it.nextURL
+}
+
+// PrevURL returns the URL of the page before the last page fetched, if any.
+func (it *Iter[T]) PrevURL() string {
+	return it.prevURL
+}
+
+// LastURL returns the URL of the last page of the list, once a page has been fetched.
+func (it *Iter[T]) LastURL() string {
+	return it.lastURL
+}
+
+// Take limits the iterator to at most n more items.
+func (it *Iter[T]) Take(n int) *Iter[T] {
+	it.limit = it.count + n
+	return it
+}
+
+// All returns a range-over-func sequence of the remaining items. The iteration
+// stops after yielding the error, if any, as the second value. The iterator is
+// closed when the iteration ends, including when breaking out of the loop.
+//
+//	for a, err := range client.ListApplicants().All(ctx) {
+//		if err != nil {
+//			return err
+//		}
+//		...
+//	}
+func (it *Iter[T]) All(ctx context.Context) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer it.Close()
+		for it.Next(ctx) {
+			if !yield(it.Current(), nil) {
+				return
+			}
+		}
+		if it.Err() != nil {
+			var zero T
+			yield(zero, it.Err())
+		}
+	}
+}
+
+// Collect gathers up to limit remaining items in a slice, or all
+// of them if limit is zero or less. The iterator is closed once done.
+func (it *Iter[T]) Collect(ctx context.Context, limit int) ([]T, error) {
+	var items []T
+	for it.Next(ctx) {
+		items = append(items, it.Current())
+		if limit > 0 && len(items) >= limit {
+			it.Close()
+			break
+		}
+	}
+	return items, it.Err()
+}
@@ -0,0 +1,75 @@
+package onfido
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeItems(t *testing.T) {
+	body := `{"meta":{"pages":[1,2],"next":null},"applicants":[{"id":"1"},{"id":"2"}],"count":2}`
+	items, err := decodeItems[*Applicant](strings.NewReader(body), "applicants")
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "1", items[0].ID)
+		assert.Equal(t, "2", items[1].ID)
+	}
+
+	items, err = decodeItems[*Applicant](strings.NewReader(`{"applicants":null}`), "applicants")
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	_, err = decodeItems[*Applicant](strings.NewReader(`{"applicants":{"id":"1"}}`), "applicants")
+	assert.Error(t, err)
+
+	_, err = decodeItems[*Applicant](strings.NewReader(`{"applicants":[{"id":"1"}`), "applicants")
+	assert.Error(t, err)
+}
+
+func listBody(n int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString(`{"applicants":[`)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		fmt.Fprintf(&buf, `{"id":"%d","first_name":"Jane","last_name":"Doe","email":"jane@example.com",`+
+			`"address":{"street":"Main Street","town":"London","postcode":"SW4 6EH","country":"GBR"}}`, i)
+	}
+	buf.WriteString(`]}`)
+	return buf.Bytes()
+}
+
+// BenchmarkDecodeItems compares decoding a list page straight from the
+// response body with the former buffer and unmarshal approach.
+func BenchmarkDecodeItems(b *testing.B) {
+	body := listBody(1000)
+
+	b.Run("stream", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := decodeItems[*Applicant](bytes.NewReader(body), "applicants"); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("buffered", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			var buf bytes.Buffer
+			if _, err := io.Copy(&buf, bytes.NewReader(body)); err != nil {
+				b.Fatal(err)
+			}
+			var items map[string][]*Applicant
+			if err := json.Unmarshal(buf.Bytes(), &items); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,303 @@
+package onfido_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	onfido "github.com/uw-labs/go-onfido"
+)
+
+// newPagedServer serves pages of applicants with IDs from 1 to total.
+func newPagedServer(t *testing.T, total, perPage int) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		if n, err := strconv.Atoi(r.URL.Query().Get("per_page")); err == nil {
+			perPage = n
+		}
+		lastPage := (total + perPage - 1) / perPage
+
+		var applicants []*onfido.Applicant
+		for id := (page-1)*perPage + 1; id <= total && id <= page*perPage; id++ {
+			applicants = append(applicants, &onfido.Applicant{ID: strconv.Itoa(id)})
+		}
+		var links []string
+		if page < lastPage {
+			links = append(links, fmt.Sprintf("<%s/applicants?page=%d&per_page=%d>; rel=\"next\"", srv.URL, page+1, perPage))
+		}
+		if page > 1 {
+			links = append(links, fmt.Sprintf("<%s/applicants?page=%d&per_page=%d>; rel=\"prev\"", srv.URL, page-1, perPage))
+		}
+		links = append(links, fmt.Sprintf("<%s/applicants?page=%d&per_page=%d>; rel=\"last\"", srv.URL, lastPage, perPage))
+		w.Header().Set("Link", strings.Join(links, ", "))
+		w.Header().Set(onfido.TotalCountHeader, strconv.Itoa(total))
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(onfido.Applicants{Applicants: applicants}))
+	}))
+	return srv
+}
+
+func ids(applicants []*onfido.Applicant) []string {
+	out := make([]string, len(applicants))
+	for i, a := range applicants {
+		out[i] = a.ID
+	}
+	return out
+}
+
+func TestIter_All(t *testing.T) {
+	srv := newPagedServer(t, 5, 2)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	var got []string
+	for a, err := range client.ListApplicants().All(context.Background()) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, a.ID)
+	}
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, got)
+}
+
+func TestIter_AllBreak(t *testing.T) {
+	srv := newPagedServer(t, 5, 2)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	it := client.ListApplicants()
+	for a := range it.All(context.Background()) {
+		if a.ID == "3" {
+			break
+		}
+	}
+	rest, err := it.Collect(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"4", "5"}, ids(rest))
+}
+
+func TestIter_AllError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	var errs []error
+	for a, err := range client.ListChecks("123").All(context.Background()) {
+		assert.Nil(t, a)
+		errs = append(errs, err)
+	}
+	if assert.Len(t, errs, 1) {
+		assert.Error(t, errs[0])
+	}
+}
+
+func TestIter_Collect(t *testing.T) {
+	srv := newPagedServer(t, 5, 2)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	all, err := client.ListApplicants().Collect(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5"}, ids(all))
+
+	some, err := client.ListApplicants().Collect(context.Background(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3"}, ids(some))
+}
+
+func TestIter_Take(t *testing.T) {
+	srv := newPagedServer(t, 5, 2)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	var got []string
+	for a, err := range client.ListApplicants().Take(3).All(context.Background()) {
+		assert.NoError(t, err)
+		got = append(got, a.ID)
+	}
+	assert.Equal(t, []string{"1", "2", "3"}, got)
+}
+
+func TestIter_DeprecatedAccessor(t *testing.T) {
+	srv := newPagedServer(t, 1, 2)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	it := client.ListApplicants()
+	assert.True(t, it.Next(context.Background()))
+	assert.Equal(t, it.Current(), it.Applicant())
+}
+
+func TestIter_ListOptions(t *testing.T) {
+	srv := newPagedServer(t, 5, 2)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	it := client.ListApplicants(onfido.Page(2), onfido.PerPage(3))
+	_, ok := it.TotalCount()
+	assert.False(t, ok)
+
+	all, err := it.Collect(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"4", "5"}, ids(all))
+
+	total, ok := it.TotalCount()
+	assert.True(t, ok)
+	assert.Equal(t, 5, total)
+	assert.Equal(t, "", it.NextURL())
+	assert.Equal(t, srv.URL+"/applicants?page=1&per_page=3", it.PrevURL())
+	assert.Equal(t, srv.URL+"/applicants?page=2&per_page=3", it.LastURL())
+}
+
+func TestIter_ListOptionsQuery(t *testing.T) {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	client.ListApplicants(onfido.IncludeDeleted(), onfido.PerPage(50)).Next(ctx)
+	client.ListChecks("a1", onfido.Page(3)).Next(ctx)
+	client.ListDocuments("a1", onfido.PerPage(10)).Next(ctx)
+	client.ListLivePhotos("a1", onfido.PerPage(10)).Next(ctx)
+	client.ListReports("c1", onfido.PerPage(10)).Next(ctx)
+	client.ListWebhooks(onfido.Page(2)).Next(ctx)
+	client.PickAddresses("SW4 6EH", onfido.PerPage(5)).Next(ctx)
+
+	assert.Equal(t, []string{
+		"/applicants?include_deleted=true&per_page=50",
+		"/checks?applicant_id=a1&page=3",
+		"/documents?applicant_id=a1&per_page=10",
+		"/live_photos?applicant_id=a1&per_page=10",
+		"/reports?check_id=c1&per_page=10",
+		"/webhooks/?page=2",
+		"/addresses/pick?per_page=5&postcode=SW4+6EH",
+	}, queries)
+}
+
+func TestIter_Prefetch(t *testing.T) {
+	srv := newPagedServer(t, 7, 2)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	it := client.ListApplicants().Prefetch(2)
+	all, err := it.Collect(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"1", "2", "3", "4", "5", "6", "7"}, ids(all))
+	total, _ := it.TotalCount()
+	assert.Equal(t, 7, total)
+}
+
+func TestIter_PrefetchAhead(t *testing.T) {
+	requested := make(chan string, 10)
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page := r.URL.Query().Get("page")
+		requested <- page
+		if page == "" {
+			w.Header().Set("Link", "<"+srv.URL+"/applicants?page=2>; rel=\"next\"")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"applicants":[{"id":"1"}]}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	it := client.ListApplicants().Prefetch(1)
+	defer it.Close()
+	assert.True(t, it.Next(context.Background()))
+	assert.Equal(t, "", <-requested)
+	// the second page is fetched while the first one is being consumed
+	select {
+	case page := <-requested:
+		assert.Equal(t, "2", page)
+	case <-time.After(time.Second):
+		t.Fatal("expected the second page to be prefetched")
+	}
+}
+
+func TestIter_PrefetchError(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Link", "<"+srv.URL+"/applicants?page=2>; rel=\"next\"")
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"applicants":[{"id":"1"},{"id":"2"}]}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	all, err := client.ListApplicants().Prefetch(3).Collect(context.Background(), 0)
+	assert.Equal(t, []string{"1", "2"}, ids(all))
+	assert.True(t, errors.Is(err, onfido.ErrServer))
+}
+
+func TestIter_PrefetchAbandoned(t *testing.T) {
+	srv := newPagedServer(t, 100, 1)
+	defer srv.Close()
+	transport := &http.Transport{}
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL), onfido.WithHTTPClient(&http.Client{Transport: transport}))
+
+	// connections kept alive have goroutines of their own
+	goroutines := func() int {
+		transport.CloseIdleConnections()
+		return runtime.NumGoroutine()
+	}
+	before := goroutines()
+
+	for i := 0; i < 20; i++ {
+		for range client.ListApplicants().Prefetch(2).All(context.Background()) {
+			break
+		}
+		all, err := client.ListApplicants().Prefetch(2).Collect(context.Background(), 2)
+		assert.NoError(t, err)
+		assert.Len(t, all, 2)
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for goroutines() > before && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	after := goroutines()
+	assert.True(t, after <= before, "%d goroutines left behind", after-before)
+}
+
+func TestIter_PrefetchCancelled(t *testing.T) {
+	srv := newPagedServer(t, 100, 1)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	it := client.ListApplicants().Prefetch(2)
+	assert.True(t, it.Next(ctx))
+	cancel()
+
+	for it.Next(ctx) {
+	}
+	assert.True(t, errors.Is(it.Err(), context.Canceled))
+}
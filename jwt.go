@@ -30,7 +30,7 @@ func (c *Client) NewSdkToken(ctx context.Context, id, referrer string) (*SdkToke
 	}
 
 	var resp SdkToken
-	if _, err := c.do(ctx, req, &resp); err != nil {
+	if _, err := c.do(withOperation(ctx, "NewSdkToken"), req, &resp); err != nil {
 		return nil, err
 	}
 	t.Token = resp.Token
@@ -1,10 +1,35 @@
 package onfido
 
 import (
-	"encoding/json"
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"strconv"
 	"time"
 )
 
+// MaxLivePhotoSize is the maximum size in bytes of an uploaded live photo
+const MaxLivePhotoSize = 10 << 20
+
+// livePhotoFormat lists the JPG and PNG files accepted for live photos.
+var livePhotoFormat = fileFormat{
+	contentTypes: []string{"image/jpeg", "image/png"},
+	maxSize:      MaxLivePhotoSize,
+}
+
+// LivePhotoRequest represents a live photo upload request to Onfido API
+type LivePhotoRequest struct {
+	File io.ReadSeeker
+	// FileName is the name the file is uploaded with, taken from File when it's an *os.File.
+	FileName string
+	// ContentType is the content type of the file, sniffed from its content when empty.
+	ContentType string
+	ApplicantID string
+	// AdvancedValidation validates that the photo contains exactly one face, it defaults to true.
+	AdvancedValidation *bool
+}
+
 // LivePhoto represents a LivePhoto in Onfido API
 type LivePhoto struct {
 	ID           string     `json:"id,omitempty"`
@@ -16,36 +41,77 @@ type LivePhoto struct {
 	FileSize     int32      `json:"file_size,omitempty"`
 }
 
-// LivePhotoIter represents a LivePhoto iterator
+// UploadLivePhoto uploads a live photo of the applicant, for facial similarity photo reports.
+// Files which aren't JPG or PNG, or which are larger than MaxLivePhotoSize,
+// are rejected with a *FileError before being sent.
+// see https://documentation.onfido.com/?shell#upload-live-photo
+func (c *Client) UploadLivePhoto(ctx context.Context, lr LivePhotoRequest) (*LivePhoto, error) {
+	file, err := newFormFile("file", lr.File, lr.FileName, lr.ContentType)
+	if err != nil {
+		return nil, err
+	}
+	if err := file.validate(livePhotoFormat); err != nil {
+		return nil, err
+	}
+	fields := []formField{{"applicant_id", lr.ApplicantID}}
+	if lr.AdvancedValidation != nil {
+		fields = append(fields, formField{"advanced_validation", strconv.FormatBool(*lr.AdvancedValidation)})
+	}
+
+	var resp LivePhoto
+	err = c.upload(withOperation(ctx, "UploadLivePhoto"), "/live_photos", file, fields, &resp)
+	return &resp, err
+}
+
+// GetLivePhoto retrieves a single live photo by its ID.
+// see https://documentation.onfido.com/?shell#retrieve-live-photo
+func (c *Client) GetLivePhoto(ctx context.Context, id string) (*LivePhoto, error) {
+	req, err := c.newRequest("GET", "/live_photos/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp LivePhoto
+	_, err = c.do(withOperation(ctx, "GetLivePhoto"), req, &resp)
+	return &resp, err
+}
+
+// DownloadLivePhoto downloads the file data for a live photo by its ID.
+// see https://documentation.onfido.com/?shell#download-live-photo
+func (c *Client) DownloadLivePhoto(ctx context.Context, id string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := c.DownloadLivePhotoTo(ctx, id, &buf)
+	return buf.Bytes(), err
+}
+
+// DownloadLivePhotoTo downloads the file data for a live photo by its ID, copying it to w
+// as it is received.
+func (c *Client) DownloadLivePhotoTo(ctx context.Context, id string, w io.Writer) error {
+	return c.downloadTo(withOperation(ctx, "DownloadLivePhoto"), "/live_photos/"+id+"/download", w)
+}
+
+// DownloadLivePhotoStream downloads the file data for a live photo by its ID, returning
+// the response body for the caller to read and close.
+func (c *Client) DownloadLivePhotoStream(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.downloadStream(withOperation(ctx, "DownloadLivePhoto"), "/live_photos/"+id+"/download")
+}
+
+// LivePhotoIter represents a LivePhoto iterator.
+// It only adds the deprecated LivePhoto accessor to Iter[*LivePhoto].
 type LivePhotoIter struct {
-	*iter
+	*Iter[*LivePhoto]
 }
 
 // LivePhoto returns the current item in the iterator as a LivePhoto.
+//
+// Deprecated: use Current.
 func (i *LivePhotoIter) LivePhoto() *LivePhoto {
-	return i.Current().(*LivePhoto)
+	return i.Current()
 }
 
 // ListPhotos retrieves the list of photos for the provided applicant.
 // see https://documentation.onfido.com/?shell#live-photos
-func (c *Client) ListLivePhotos(applicantID string) *LivePhotoIter {
-	return &LivePhotoIter{&iter{
-		c:       c,
-		nextURL: "/live_photos?applicant_id=" + applicantID,
-		handler: func(body []byte) ([]interface{}, error) {
-			var r struct {
-				LivePhotos []*LivePhoto `json:"live_photos"`
-			}
-
-			if err := json.Unmarshal(body, &r); err != nil {
-				return nil, err
-			}
-
-			values := make([]interface{}, len(r.LivePhotos))
-			for i, v := range r.LivePhotos {
-				values[i] = v
-			}
-			return values, nil
-		},
-	}}
+func (c *Client) ListLivePhotos(applicantID string, opts ...ListOption) *LivePhotoIter {
+	params := url.Values{"applicant_id": {applicantID}}
+	return &LivePhotoIter{newIter[*LivePhoto](c, "ListLivePhotos", "live_photos", listURL("/live_photos", params, opts))}
 }
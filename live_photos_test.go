@@ -1,8 +1,11 @@
 package onfido_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -67,3 +70,100 @@ func TestLivePhotos_List(t *testing.T) {
 		t.Fatal(it.Err())
 	}
 }
+
+// jpegFile starts with the JPEG signature so that it is accepted as a live photo
+var jpegFile = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00selfie")
+
+func TestUploadLivePhoto(t *testing.T) {
+	m := mux.NewRouter()
+	m.HandleFunc("/live_photos", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "541d040b", r.FormValue("applicant_id"))
+		assert.Equal(t, "false", r.FormValue("advanced_validation"))
+		file, header, err := r.FormFile("file")
+		if assert.NoError(t, err) {
+			assert.Equal(t, "selfie.jpg", header.Filename)
+			assert.Equal(t, "image/jpeg", header.Header.Get("Content-Type"))
+			data, err := ioutil.ReadAll(file)
+			assert.NoError(t, err)
+			assert.Equal(t, jpegFile, data)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_, wErr := w.Write([]byte(`{"id":"123","file_name":"selfie.jpg","file_type":"image/jpeg"}`))
+		assert.NoError(t, wErr)
+	}).Methods("POST")
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	advanced := false
+	p, err := client.UploadLivePhoto(context.Background(), onfido.LivePhotoRequest{
+		File:               bytes.NewReader(jpegFile),
+		FileName:           "selfie.jpg",
+		ApplicantID:        "541d040b",
+		AdvancedValidation: &advanced,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "123", p.ID)
+	assert.Equal(t, "selfie.jpg", p.FileName)
+}
+
+func TestUploadLivePhoto_UnsupportedFile(t *testing.T) {
+	client := onfido.NewClient("123", onfido.WithBaseURL("http://127.0.0.1:0"))
+	_, err := client.UploadLivePhoto(context.Background(), onfido.LivePhotoRequest{
+		File: bytes.NewReader([]byte("%PDF-1.4")),
+	})
+	assert.True(t, errors.Is(err, onfido.ErrUnsupportedFileType))
+	assert.True(t, errors.Is(err, onfido.ErrValidation))
+}
+
+func TestGetLivePhoto(t *testing.T) {
+	m := mux.NewRouter()
+	m.HandleFunc("/live_photos/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] != "123" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123","file_size":1234}`))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	p, err := client.GetLivePhoto(context.Background(), "123")
+	assert.NoError(t, err)
+	assert.Equal(t, "123", p.ID)
+	assert.Equal(t, int32(1234), p.FileSize)
+
+	_, err = client.GetLivePhoto(context.Background(), "456")
+	assert.True(t, errors.Is(err, onfido.ErrNotFound))
+}
+
+func TestDownloadLivePhoto(t *testing.T) {
+	m := mux.NewRouter()
+	m.HandleFunc("/live_photos/{id}/download", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, wErr := w.Write(jpegFile)
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	data, err := client.DownloadLivePhoto(ctx, "123")
+	assert.NoError(t, err)
+	assert.Equal(t, jpegFile, data)
+
+	rc, err := client.DownloadLivePhotoStream(ctx, "123")
+	if assert.NoError(t, err) {
+		data, err = ioutil.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.Equal(t, jpegFile, data)
+		assert.NoError(t, rc.Close())
+	}
+}
@@ -0,0 +1,90 @@
+package onfido
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"time"
+)
+
+// LiveVideoChallenge represents a challenge the applicant was asked to perform in a live video,
+// reciting digits or moving their head.
+type LiveVideoChallenge struct {
+	// Type is either `recite` or `movement`
+	Type string `json:"type,omitempty"`
+	// Query holds the digits to recite, e.g. []interface{}{1, 2, 3}, or the movement, e.g. `turnLeft`
+	Query interface{} `json:"query,omitempty"`
+}
+
+// LiveVideo represents a live video in Onfido API
+type LiveVideo struct {
+	ID           string               `json:"id,omitempty"`
+	CreatedAt    *time.Time           `json:"created_at,omitempty"`
+	Href         string               `json:"href,omitempty"`
+	DownloadHref string               `json:"download_href,omitempty"`
+	FileName     string               `json:"file_name,omitempty"`
+	FileType     string               `json:"file_type,omitempty"`
+	FileSize     int32                `json:"file_size,omitempty"`
+	Challenge    []LiveVideoChallenge `json:"challenge,omitempty"`
+}
+
+// GetLiveVideo retrieves a single live video by its ID.
+// see https://documentation.onfido.com/?shell#retrieve-live-video
+func (c *Client) GetLiveVideo(ctx context.Context, id string) (*LiveVideo, error) {
+	req, err := c.newRequest("GET", "/live_videos/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp LiveVideo
+	_, err = c.do(withOperation(ctx, "GetLiveVideo"), req, &resp)
+	return &resp, err
+}
+
+// DownloadLiveVideo downloads the file data for a live video by its ID.
+// see https://documentation.onfido.com/?shell#download-live-video
+func (c *Client) DownloadLiveVideo(ctx context.Context, id string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := c.DownloadLiveVideoTo(ctx, id, &buf)
+	return buf.Bytes(), err
+}
+
+// DownloadLiveVideoTo downloads the file data for a live video by its ID, copying it to w
+// as it is received.
+func (c *Client) DownloadLiveVideoTo(ctx context.Context, id string, w io.Writer) error {
+	return c.downloadTo(withOperation(ctx, "DownloadLiveVideo"), "/live_videos/"+id+"/download", w)
+}
+
+// DownloadLiveVideoStream downloads the file data for a live video by its ID, returning
+// the response body for the caller to read and close.
+func (c *Client) DownloadLiveVideoStream(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.downloadStream(withOperation(ctx, "DownloadLiveVideo"), "/live_videos/"+id+"/download")
+}
+
+// DownloadLiveVideoFrame downloads a single frame of a live video by its ID, as a JPEG image.
+// see https://documentation.onfido.com/?shell#download-live-video-frame
+func (c *Client) DownloadLiveVideoFrame(ctx context.Context, id string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := c.DownloadLiveVideoFrameTo(ctx, id, &buf)
+	return buf.Bytes(), err
+}
+
+// DownloadLiveVideoFrameTo downloads a single frame of a live video by its ID, copying it to w
+// as it is received.
+func (c *Client) DownloadLiveVideoFrameTo(ctx context.Context, id string, w io.Writer) error {
+	return c.downloadTo(withOperation(ctx, "DownloadLiveVideoFrame"), "/live_videos/"+id+"/frame", w)
+}
+
+// DownloadLiveVideoFrameStream downloads a single frame of a live video by its ID, returning
+// the response body for the caller to read and close.
+func (c *Client) DownloadLiveVideoFrameStream(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.downloadStream(withOperation(ctx, "DownloadLiveVideoFrame"), "/live_videos/"+id+"/frame")
+}
+
+// ListLiveVideos retrieves the list of live videos for the provided applicant.
+// see https://documentation.onfido.com/?shell#list-live-videos
+func (c *Client) ListLiveVideos(applicantID string, opts ...ListOption) *Iter[*LiveVideo] {
+	params := url.Values{"applicant_id": {applicantID}}
+	return newIter[*LiveVideo](c, "ListLiveVideos", "live_videos", listURL("/live_videos", params, opts))
+}
@@ -0,0 +1,91 @@
+package onfido_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	onfido "github.com/uw-labs/go-onfido"
+)
+
+func TestLiveVideos(t *testing.T) {
+	applicantID := "541d040b-89f8-444b-8921-16b1333bf1c6"
+	video := `{"id":"c9701e9b","file_name":"video.mp4","file_type":"video/mp4","file_size":1234,` +
+		`"challenge":[{"type":"recite","query":[1,2,3]},{"type":"movement","query":"turnLeft"}]}`
+
+	m := mux.NewRouter()
+	m.HandleFunc("/live_videos", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, applicantID, r.URL.Query().Get("applicant_id"))
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"live_videos":[` + video + `]}`))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/live_videos/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] != "c9701e9b" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(video))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/live_videos/{id}/download", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/mp4")
+		_, wErr := w.Write([]byte("video"))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/live_videos/{id}/frame", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, wErr := w.Write([]byte("frame"))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	videos, err := client.ListLiveVideos(applicantID).Collect(ctx, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, videos, 1) {
+		assert.Equal(t, "c9701e9b", videos[0].ID)
+	}
+
+	v, err := client.GetLiveVideo(ctx, "c9701e9b")
+	assert.NoError(t, err)
+	assert.Equal(t, "video.mp4", v.FileName)
+	assert.Equal(t, int32(1234), v.FileSize)
+	assert.Equal(t, []onfido.LiveVideoChallenge{
+		{Type: "recite", Query: []interface{}{float64(1), float64(2), float64(3)}},
+		{Type: "movement", Query: "turnLeft"},
+	}, v.Challenge)
+
+	_, err = client.GetLiveVideo(ctx, "unknown")
+	assert.True(t, errors.Is(err, onfido.ErrNotFound))
+
+	data, err := client.DownloadLiveVideo(ctx, "c9701e9b")
+	assert.NoError(t, err)
+	assert.Equal(t, "video", string(data))
+
+	frame, err := client.DownloadLiveVideoFrame(ctx, "c9701e9b")
+	assert.NoError(t, err)
+	assert.Equal(t, "frame", string(frame))
+
+	var frameBuf bytes.Buffer
+	assert.NoError(t, client.DownloadLiveVideoFrameTo(ctx, "c9701e9b", &frameBuf))
+	assert.Equal(t, "frame", frameBuf.String())
+
+	rc, err := client.DownloadLiveVideoFrameStream(ctx, "c9701e9b")
+	if assert.NoError(t, err) {
+		frame, err = io.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.NoError(t, rc.Close())
+		assert.Equal(t, "frame", string(frame))
+	}
+}
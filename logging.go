@@ -0,0 +1,180 @@
+package onfido
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Redacted replaces sensitive values in logs
+const Redacted = "[REDACTED]"
+
+// query parameters which hold identifiers or paging details and are safe to log
+var loggableParams = map[string]bool{
+	"applicant_id": true,
+	"check_id":     true,
+	"page":         true,
+	"per_page":     true,
+}
+
+// WithLogger logs every API call made by the client to l, see LoggingMiddleware.
+func WithLogger(l *slog.Logger) Option {
+	return func(c *Client) {
+		c.Use(LoggingMiddleware(l))
+	}
+}
+
+// LoggingMiddleware returns middleware logging the operation, method, path, status,
+// duration and Onfido request ID of every API call. Successful calls are logged
+// at debug level and failed ones at error level.
+// Request and response bodies are never logged and query parameters other than
+// IDs are redacted, as they may hold personal data such as postcodes. For the same
+// reason API errors are logged by class rather than message, and transport errors
+// without the request URL.
+func LoggingMiddleware(l *slog.Logger) Middleware {
+	return func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, call *Call) (*http.Response, error) {
+			start := time.Now()
+			resp, err := next(ctx, call)
+
+			attrs := []slog.Attr{
+				slog.String("operation", call.Operation),
+				slog.String("method", call.Request.Method),
+				slog.String("path", redactedPath(call.Request.URL)),
+				slog.Duration("duration", time.Since(start)),
+				slog.Int("attempts", call.Attempts),
+			}
+			if resp != nil {
+				attrs = append(attrs, slog.Int("status", resp.StatusCode))
+				if id := resp.Header.Get(RequestIDHeader); id != "" {
+					attrs = append(attrs, slog.String("request_id", id))
+				}
+			}
+
+			if err == nil {
+				l.LogAttrs(ctx, slog.LevelDebug, "onfido request", attrs...)
+				return resp, nil
+			}
+
+			var onfidoErr *Error
+			if errors.As(err, &onfidoErr) {
+				attrs = append(attrs, slog.String("error_type", onfidoErr.Err.Type))
+			}
+			attrs = append(attrs, slog.String("error", loggableError(err)))
+			l.LogAttrs(ctx, slog.LevelError, "onfido request failed", attrs...)
+			return resp, err
+		}
+	}
+}
+
+// loggableError describes err without the parts which may hold personal data: the
+// message of API errors, which may quote the request, and the URL of transport errors.
+func loggableError(err error) string {
+	var onfidoErr *Error
+	if errors.As(err, &onfidoErr) {
+		if class := onfidoErr.class(); class != nil {
+			return class.Error()
+		}
+		if onfidoErr.Resp != nil {
+			return fmt.Sprintf("http request failed with status code %d", onfidoErr.Resp.StatusCode)
+		}
+		return "an unknown error occurred"
+	}
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Op + ": " + loggableError(urlErr.Err)
+	}
+	return err.Error()
+}
+
+func redactedPath(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	if u.RawQuery == "" {
+		return u.Path
+	}
+	q := u.Query()
+	for k, vs := range q {
+		if loggableParams[k] {
+			continue
+		}
+		for i := range vs {
+			vs[i] = Redacted
+		}
+	}
+	return u.Path + "?" + q.Encode()
+}
+
+func redact(s string) string {
+	if s == "" {
+		return ""
+	}
+	return Redacted
+}
+
+// LogValue implements slog.LogValuer, masking the applicant personal data.
+func (a Applicant) LogValue() slog.Value {
+	attrs := []slog.Attr{
+		slog.String("id", a.ID),
+		slog.Bool("sandbox", a.Sandbox),
+		slog.String("first_name", redact(a.FirstName)),
+		slog.String("middle_name", redact(a.MiddleName)),
+		slog.String("last_name", redact(a.LastName)),
+		slog.String("email", redact(a.Email)),
+		slog.String("dob", redact(a.DOB)),
+		slog.Any("location", a.Location),
+	}
+	if len(a.IDNumbers) > 0 {
+		// slices aren't resolved by slog, log each ID number as its own group
+		numbers := make([]any, len(a.IDNumbers))
+		for i, n := range a.IDNumbers {
+			numbers[i] = slog.Any(strconv.Itoa(i), n)
+		}
+		attrs = append(attrs, slog.Group("id_numbers", numbers...))
+	}
+	if a.CreatedAt != nil {
+		attrs = append(attrs, slog.Time("created_at", *a.CreatedAt))
+	}
+	if a.Address != nil {
+		attrs = append(attrs, slog.Any("address", *a.Address))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// LogValue implements slog.LogValuer, masking the ID number value.
+func (n IDNumber) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("type", string(n.Type)),
+		slog.String("value", redact(n.Value)),
+		slog.String("state_code", n.StateCode),
+	)
+}
+
+// LogValue implements slog.LogValuer, masking everything but the country.
+func (a Address) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("flat_number", redact(a.FlatNumber)),
+		slog.String("building_number", redact(a.BuildingNumber)),
+		slog.String("building_name", redact(a.BuildingName)),
+		slog.String("street", redact(a.Street)),
+		slog.String("sub_street", redact(a.SubStreet)),
+		slog.String("town", redact(a.Town)),
+		slog.String("state", redact(a.State)),
+		slog.String("postcode", redact(a.Postcode)),
+		slog.String("country", a.Country),
+	)
+}
+
+// LogValue implements slog.LogValuer, masking the IP address.
+func (l Location) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("ip_address", redact(l.IPAddress)),
+		slog.String("country_of_residence", l.CountryOfResidence),
+	)
+}
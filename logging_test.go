@@ -0,0 +1,114 @@
+package onfido
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggingMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(RequestIDHeader, "req-1")
+		if r.Method == "POST" {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, wErr := w.Write([]byte(`{"error":{"type":"validation_error","message":"There was a validation error on this request"}}`))
+			assert.NoError(t, wErr)
+			return
+		}
+		_, wErr := w.Write([]byte(`{"addresses":[]}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := NewClient("123", WithBaseURL(srv.URL), WithLogger(logger))
+
+	_, err := client.CreateApplicant(context.Background(), Applicant{FirstName: "Rob", DOB: "1990-01-31"})
+	assert.Error(t, err)
+	it := client.PickAddresses("SW4 6EH")
+	for it.Next(context.Background()) {
+	}
+	assert.NoError(t, it.Err())
+
+	assert.NotContains(t, buf.String(), "Rob")
+	assert.NotContains(t, buf.String(), "1990-01-31")
+	assert.NotContains(t, buf.String(), "SW4")
+
+	dec := json.NewDecoder(&buf)
+	var failed, picked map[string]interface{}
+	assert.NoError(t, dec.Decode(&failed))
+	assert.NoError(t, dec.Decode(&picked))
+
+	assert.Equal(t, "ERROR", failed["level"])
+	assert.Equal(t, "CreateApplicant", failed["operation"])
+	assert.Equal(t, "POST", failed["method"])
+	assert.Equal(t, "/applicants", failed["path"])
+	assert.EqualValues(t, http.StatusUnprocessableEntity, failed["status"])
+	assert.Equal(t, "req-1", failed["request_id"])
+	assert.Equal(t, "validation_error", failed["error_type"])
+	assert.Equal(t, "validation error", failed["error"])
+	assert.Contains(t, failed, "duration")
+
+	assert.Equal(t, "DEBUG", picked["level"])
+	assert.Equal(t, "PickAddresses", picked["operation"])
+	assert.Equal(t, "/addresses/pick?postcode=%5BREDACTED%5D", picked["path"])
+	assert.EqualValues(t, http.StatusOK, picked["status"])
+}
+
+func TestLoggingMiddleware_TransportError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	client := NewClient("123", WithBaseURL(srv.URL), WithLogger(logger))
+
+	it := client.PickAddresses("SW4 6EH")
+	for it.Next(context.Background()) {
+	}
+	assert.Error(t, it.Err())
+	assert.NotContains(t, buf.String(), "SW4")
+
+	var failed map[string]interface{}
+	assert.NoError(t, json.NewDecoder(&buf).Decode(&failed))
+	assert.Equal(t, "/addresses/pick?postcode=%5BREDACTED%5D", failed["path"])
+	assert.Contains(t, failed["error"], "Get: ")
+}
+
+func TestApplicant_LogValue(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	logger.Info("applicant", "applicant", &Applicant{
+		ID:        "541d040b-89f8-444b-8921-16b1333bf1c6",
+		FirstName: "Rob",
+		LastName:  "Crowe",
+		Email:     "rcrowe@example.co.uk",
+		DOB:       "1990-01-31",
+		IDNumbers: []IDNumber{{Type: IDNumberTypeSSN, Value: "433-54-3937"}},
+		Address: &Address{
+			BuildingNumber: "18",
+			Street:         "Wind Corner",
+			Postcode:       "NW9 5AB",
+			Country:        "GBR",
+		},
+		Location: Location{IPAddress: "127.0.0.1", CountryOfResidence: "GBR"},
+	})
+
+	out := buf.String()
+	for _, pii := range []string{"Rob", "Crowe", "rcrowe", "1990-01-31", "433-54-3937", "Wind Corner", "NW9", "127.0.0.1"} {
+		assert.NotContains(t, out, pii)
+	}
+	assert.Contains(t, out, "541d040b-89f8-444b-8921-16b1333bf1c6")
+	assert.Contains(t, out, `"country":"GBR"`)
+	assert.Contains(t, out, `"type":"ssn"`)
+	assert.Contains(t, out, Redacted)
+}
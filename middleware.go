@@ -0,0 +1,83 @@
+package onfido
+
+import (
+	"context"
+	"net/http"
+)
+
+// Call describes a single API call as seen by the client middleware.
+type Call struct {
+	// Operation is the name of the client method making the call,
+	// e.g. "CreateApplicant", "GetCheck" or "ListReports" for list page fetches.
+	Operation string
+	// Request is the request about to be sent, middleware may modify it before calling next.
+	Request *http.Request
+	// Attempts is the number of requests sent for the call, including retries.
+	// It is set once the call completes.
+	Attempts int
+}
+
+// RoundTripFunc performs an API call. It returns the response and, for
+// unsuccessful responses, the decoded *Error alongside it, the body of the
+// response being closed already.
+type RoundTripFunc func(ctx context.Context, call *Call) (*http.Response, error)
+
+// Middleware wraps the RoundTripFunc performing every API call of a client,
+// including list page fetches, to add behaviour such as tracing or logging.
+type Middleware func(next RoundTripFunc) RoundTripFunc
+
+// Use adds middleware to the client. Middleware registered first is the
+// outermost, i.e. the first one to see the call and the last one to see the result.
+func (c *Client) Use(mw ...Middleware) {
+	c.middleware = append(c.middleware, mw...)
+}
+
+// WithMiddleware adds middleware to the client, see Client.Use.
+func WithMiddleware(mw ...Middleware) Option {
+	return func(c *Client) {
+		c.Use(mw...)
+	}
+}
+
+type operationKey struct{}
+
+func withOperation(ctx context.Context, op string) context.Context {
+	return context.WithValue(ctx, operationKey{}, op)
+}
+
+// OperationFromContext returns the name of the API operation a request
+// context belongs to, making it available to HTTPRequester implementations.
+func OperationFromContext(ctx context.Context) string {
+	op, _ := ctx.Value(operationKey{}).(string)
+	return op
+}
+
+// roundTrip runs the call through the client middleware.
+func (c *Client) roundTrip(ctx context.Context, req *http.Request) (*http.Response, error) {
+	call := &Call{
+		Operation: OperationFromContext(ctx),
+		Request:   req,
+	}
+
+	next := c.transport
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		next = c.middleware[i](next)
+	}
+	return next(ctx, call)
+}
+
+// transport is the innermost RoundTripFunc, sending the request and decoding API errors.
+// The body of a failed response is closed once decoded, the response being only
+// returned for its status and headers.
+func (c *Client) transport(ctx context.Context, call *Call) (*http.Response, error) {
+	resp, err := c.send(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+	if c := resp.StatusCode; c < 200 || c > 299 {
+		err := handleResponseErr(resp)
+		drain(resp)
+		return resp, err
+	}
+	return resp, nil
+}
@@ -0,0 +1,168 @@
+package onfido
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMiddleware_Order(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123"}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next RoundTripFunc) RoundTripFunc {
+			return func(ctx context.Context, call *Call) (*http.Response, error) {
+				calls = append(calls, name+" before")
+				resp, err := next(ctx, call)
+				calls = append(calls, name+" after")
+				return resp, err
+			}
+		}
+	}
+
+	client := NewClient("123", WithBaseURL(srv.URL), WithMiddleware(record("first")))
+	client.Use(record("second"))
+
+	_, err := client.GetApplicant(context.Background(), "123")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first before", "second before", "second after", "first after"}, calls)
+}
+
+func TestMiddleware_SeesCall(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "trace-1", r.Header.Get("X-Trace-Id"))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, wErr := w.Write([]byte(`{"error":{"type":"resource_not_found","message":"not found"}}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	var (
+		seen     *Call
+		seenResp *http.Response
+		seenErr  error
+	)
+	client := NewClient("123", WithBaseURL(srv.URL))
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, call *Call) (*http.Response, error) {
+			call.Request.Header.Set("X-Trace-Id", "trace-1")
+			resp, err := next(ctx, call)
+			seen, seenResp, seenErr = call, resp, err
+			return resp, err
+		}
+	})
+
+	_, err := client.GetCheck(context.Background(), "123")
+	assert.True(t, errors.Is(err, ErrNotFound))
+
+	assert.Equal(t, "GetCheck", seen.Operation)
+	assert.Equal(t, "/checks/123", seen.Request.URL.Path)
+	assert.Equal(t, 1, seen.Attempts)
+	assert.Equal(t, http.StatusNotFound, seenResp.StatusCode)
+	var onfidoErr *Error
+	if assert.True(t, errors.As(seenErr, &onfidoErr)) {
+		assert.Equal(t, "not found", onfidoErr.Err.Msg)
+	}
+}
+
+func TestMiddleware_WrapsListPages(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", "<"+srv.URL+"/reports?check_id=1&page=2>; rel=\"next\"")
+		}
+		_, wErr := w.Write([]byte(`{"reports":[{"id":"1"}]}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	var ops []string
+	client := NewClient("123", WithBaseURL(srv.URL))
+	client.Use(func(next RoundTripFunc) RoundTripFunc {
+		return func(ctx context.Context, call *Call) (*http.Response, error) {
+			ops = append(ops, call.Operation)
+			return next(ctx, call)
+		}
+	})
+
+	it := client.ListReports("1")
+	for it.Next(context.Background()) {
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, []string{"ListReports", "ListReports"}, ops)
+}
+
+func TestOperationFromContext(t *testing.T) {
+	var op string
+	client := NewClient("123")
+	client.HTTPClient = requesterFunc(func(req *http.Request) (*http.Response, error) {
+		op = OperationFromContext(req.Context())
+		return nil, errors.New("boom")
+	})
+
+	_, err := client.GetDocument(context.Background(), "123")
+	assert.Error(t, err)
+	assert.Equal(t, "GetDocument", op)
+	assert.Equal(t, "", OperationFromContext(context.Background()))
+}
+
+func TestTransport_ClosesErrorBodies(t *testing.T) {
+	var bodies []*closeRecorder
+	client := NewClient("123")
+	client.HTTPClient = requesterFunc(func(req *http.Request) (*http.Response, error) {
+		body := &closeRecorder{Reader: strings.NewReader("<html>Bad Gateway</html>")}
+		bodies = append(bodies, body)
+		return &http.Response{
+			StatusCode: http.StatusBadGateway,
+			Header:     http.Header{"Content-Type": {"text/html"}},
+			Body:       body,
+		}, nil
+	})
+	ctx := context.Background()
+
+	_, err := client.GetApplicant(ctx, "123")
+	assert.True(t, errors.Is(err, ErrServer))
+	_, err = client.DownloadDocument(ctx, "123")
+	assert.True(t, errors.Is(err, ErrServer))
+	_, err = client.DownloadDocumentStream(ctx, "123")
+	assert.True(t, errors.Is(err, ErrServer))
+	it := client.ListApplicants()
+	assert.False(t, it.Next(ctx))
+	assert.True(t, errors.Is(it.Err(), ErrServer))
+
+	if assert.Len(t, bodies, 4) {
+		for _, body := range bodies {
+			assert.True(t, body.closed)
+		}
+	}
+}
+
+type closeRecorder struct {
+	io.Reader
+	closed bool
+}
+
+func (r *closeRecorder) Close() error {
+	r.closed = true
+	return nil
+}
+
+type requesterFunc func(*http.Request) (*http.Response, error)
+
+func (f requesterFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
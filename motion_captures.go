@@ -0,0 +1,80 @@
+package onfido
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/url"
+	"time"
+)
+
+// MotionCapture represents a Motion capture in Onfido API
+type MotionCapture struct {
+	ID           string     `json:"id,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	Href         string     `json:"href,omitempty"`
+	DownloadHref string     `json:"download_href,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	FileType     string     `json:"file_type,omitempty"`
+	FileSize     int32      `json:"file_size,omitempty"`
+}
+
+// GetMotionCapture retrieves a single Motion capture by its ID.
+// see https://documentation.onfido.com/?shell#retrieve-motion-capture
+func (c *Client) GetMotionCapture(ctx context.Context, id string) (*MotionCapture, error) {
+	req, err := c.newRequest("GET", "/motion_captures/"+id, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp MotionCapture
+	_, err = c.do(withOperation(ctx, "GetMotionCapture"), req, &resp)
+	return &resp, err
+}
+
+// DownloadMotionCapture downloads the file data for a Motion capture by its ID.
+// see https://documentation.onfido.com/?shell#download-motion-capture
+func (c *Client) DownloadMotionCapture(ctx context.Context, id string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := c.DownloadMotionCaptureTo(ctx, id, &buf)
+	return buf.Bytes(), err
+}
+
+// DownloadMotionCaptureTo downloads the file data for a Motion capture by its ID, copying it to w
+// as it is received.
+func (c *Client) DownloadMotionCaptureTo(ctx context.Context, id string, w io.Writer) error {
+	return c.downloadTo(withOperation(ctx, "DownloadMotionCapture"), "/motion_captures/"+id+"/download", w)
+}
+
+// DownloadMotionCaptureStream downloads the file data for a Motion capture by its ID, returning
+// the response body for the caller to read and close.
+func (c *Client) DownloadMotionCaptureStream(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.downloadStream(withOperation(ctx, "DownloadMotionCapture"), "/motion_captures/"+id+"/download")
+}
+
+// DownloadMotionCaptureFrame downloads a single frame of a Motion capture by its ID, as a JPEG image.
+// see https://documentation.onfido.com/?shell#download-motion-capture-frame
+func (c *Client) DownloadMotionCaptureFrame(ctx context.Context, id string) ([]byte, error) {
+	var buf bytes.Buffer
+	err := c.DownloadMotionCaptureFrameTo(ctx, id, &buf)
+	return buf.Bytes(), err
+}
+
+// DownloadMotionCaptureFrameTo downloads a single frame of a Motion capture by its ID, copying it to w
+// as it is received.
+func (c *Client) DownloadMotionCaptureFrameTo(ctx context.Context, id string, w io.Writer) error {
+	return c.downloadTo(withOperation(ctx, "DownloadMotionCaptureFrame"), "/motion_captures/"+id+"/frame", w)
+}
+
+// DownloadMotionCaptureFrameStream downloads a single frame of a Motion capture by its ID, returning
+// the response body for the caller to read and close.
+func (c *Client) DownloadMotionCaptureFrameStream(ctx context.Context, id string) (io.ReadCloser, error) {
+	return c.downloadStream(withOperation(ctx, "DownloadMotionCaptureFrame"), "/motion_captures/"+id+"/frame")
+}
+
+// ListMotionCaptures retrieves the list of Motion captures for the provided applicant.
+// see https://documentation.onfido.com/?shell#list-motion-captures
+func (c *Client) ListMotionCaptures(applicantID string, opts ...ListOption) *Iter[*MotionCapture] {
+	params := url.Values{"applicant_id": {applicantID}}
+	return newIter[*MotionCapture](c, "ListMotionCaptures", "motion_captures", listURL("/motion_captures", params, opts))
+}
@@ -0,0 +1,86 @@
+package onfido_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	onfido "github.com/uw-labs/go-onfido"
+)
+
+func TestMotionCaptures(t *testing.T) {
+	applicantID := "541d040b-89f8-444b-8921-16b1333bf1c6"
+	capture := `{"id":"b1ae05b7","file_name":"capture.mp4","file_type":"video/mp4","file_size":4321}`
+
+	m := mux.NewRouter()
+	m.HandleFunc("/motion_captures", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, applicantID, r.URL.Query().Get("applicant_id"))
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"motion_captures":[` + capture + `]}`))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/motion_captures/{id}", func(w http.ResponseWriter, r *http.Request) {
+		if mux.Vars(r)["id"] != "b1ae05b7" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(capture))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/motion_captures/{id}/download", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/mp4")
+		_, wErr := w.Write([]byte("capture"))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/motion_captures/{id}/frame", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, wErr := w.Write([]byte("frame"))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	srv := httptest.NewServer(m)
+	defer srv.Close()
+
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	ctx := context.Background()
+
+	captures, err := client.ListMotionCaptures(applicantID).Collect(ctx, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, captures, 1) {
+		assert.Equal(t, "b1ae05b7", captures[0].ID)
+	}
+
+	mc, err := client.GetMotionCapture(ctx, "b1ae05b7")
+	assert.NoError(t, err)
+	assert.Equal(t, "capture.mp4", mc.FileName)
+	assert.Equal(t, int32(4321), mc.FileSize)
+
+	_, err = client.GetMotionCapture(ctx, "unknown")
+	assert.True(t, errors.Is(err, onfido.ErrNotFound))
+
+	var buf bytes.Buffer
+	assert.NoError(t, client.DownloadMotionCaptureTo(ctx, "b1ae05b7", &buf))
+	assert.Equal(t, "capture", buf.String())
+
+	frame, err := client.DownloadMotionCaptureFrame(ctx, "b1ae05b7")
+	assert.NoError(t, err)
+	assert.Equal(t, "frame", string(frame))
+
+	var frameBuf bytes.Buffer
+	assert.NoError(t, client.DownloadMotionCaptureFrameTo(ctx, "b1ae05b7", &frameBuf))
+	assert.Equal(t, "frame", frameBuf.String())
+
+	rc, err := client.DownloadMotionCaptureFrameStream(ctx, "b1ae05b7")
+	if assert.NoError(t, err) {
+		frame, err = io.ReadAll(rc)
+		assert.NoError(t, err)
+		assert.NoError(t, rc.Close())
+		assert.Equal(t, "frame", string(frame))
+	}
+}
@@ -1,17 +1,14 @@
 package onfido
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
-
-	"github.com/tomnomnom/linkheader"
 )
 
 // Constants
@@ -19,6 +16,10 @@ const (
 	ClientVersion   = "0.1.0"
 	DefaultEndpoint = "https://api.eu.onfido.com/v3.6"
 	TokenEnv        = "ONFIDO_TOKEN"
+	RegionEnv       = "ONFIDO_REGION"
+	APIVersionEnv   = "ONFIDO_API_VERSION"
+	EndpointEnv     = "ONFIDO_ENDPOINT"
+	EnvironmentEnv  = "ONFIDO_ENVIRONMENT"
 )
 
 // Client represents an Onfido API client
@@ -26,6 +27,19 @@ type Client struct {
 	Endpoint   string
 	HTTPClient HTTPRequester
 	Token      Token
+	// TokenSource, when set, supplies the token for every request in place of Token.
+	TokenSource TokenSource
+	// RetryPolicy controls retries of failed requests, nil disables them.
+	RetryPolicy *RetryPolicy
+	// RateLimiter, when set, is waited on before every request, including retries
+	// and list page fetches.
+	RateLimiter *RateLimiter
+
+	region          Region
+	apiVersion      string
+	userAgentSuffix string
+	middleware      []Middleware
+	environment     Environment
 }
 
 // HTTPRequester represents an HTTP requester
@@ -36,6 +50,8 @@ type HTTPRequester interface {
 // Error represents an Onfido API error response
 type Error struct {
 	Resp *http.Response
+	// RequestID identifies the failed request with Onfido support
+	RequestID string `json:"-"`
 	// see https://documentation.onfido.com/#error-object
 	Err struct {
 		ID     string      `json:"id"`
@@ -45,7 +61,9 @@ type Error struct {
 	} `json:"error"`
 }
 
-// known shapes of the values are []string and map[string][]string for recursive field validation
+// ErrorFields holds the validation errors of a request.
+// known shapes of the values are []string and map[string][]string for recursive field validation,
+// use Flatten to read them as a list of FieldError.
 type ErrorFields map[string]interface{}
 
 func (e *Error) Error() string {
@@ -58,14 +76,58 @@ func (e *Error) Error() string {
 	return "an unknown error occurred"
 }
 
-// Token is an Onfido authentication token
+// Token is an Onfido authentication token.
+// Printing, logging or JSON encoding a token only ever shows its redacted form,
+// use Reveal to get the actual value.
 type Token string
 
-// String returns the token as a string.
-func (t Token) String() string {
+// Reveal returns the actual token value, for use in the Authorization header.
+func (t Token) Reveal() string {
 	return string(t)
 }
 
+// String returns the redacted token, keeping its prefix and last four characters,
+// e.g. `api_live.****abcd`.
+func (t Token) String() string {
+	s := string(t)
+	if s == "" {
+		return ""
+	}
+
+	// keep the `api_live.` or `test_` style prefix, it tells tokens apart without revealing them
+	var prefix string
+	i := strings.Index(s, ".")
+	if i < 0 {
+		i = strings.Index(s, "_")
+	}
+	if i >= 0 && i < len(s)-1 {
+		prefix, s = s[:i+1], s[i+1:]
+	}
+	if len(s) <= 8 {
+		return prefix + "****"
+	}
+	return prefix + "****" + s[len(s)-4:]
+}
+
+// Format implements fmt.Formatter so that every verb prints the redacted token.
+func (t Token) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		fmt.Fprintf(f, "%q", t.String())
+		return
+	}
+	_, _ = io.WriteString(f, t.String())
+}
+
+// LogValue implements slog.LogValuer, logging the redacted token.
+func (t Token) LogValue() slog.Value {
+	return slog.StringValue(t.String())
+}
+
+// MarshalJSON encodes the redacted token.
+func (t Token) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
 // Prod checks if this is a production token or not.
 func (t Token) Prod() bool {
 	return !strings.HasPrefix(string(t), "test_") &&
@@ -73,22 +135,86 @@ func (t Token) Prod() bool {
 }
 
 // NewClientFromEnv creates a new Onfido client using configuration
-// from environment variables.
-func NewClientFromEnv() (*Client, error) {
+// from environment variables. The region, API version and endpoint are
+// read from the optional `ONFIDO_REGION`, `ONFIDO_API_VERSION` and
+// `ONFIDO_ENDPOINT` variables, the provided options take precedence over them:
+// `ONFIDO_ENDPOINT` is ignored when a region, API version or base URL is provided.
+// When an environment is declared, either with `ONFIDO_ENVIRONMENT` or WithEnvironment,
+// the client is refused if the token belongs to another environment.
+func NewClientFromEnv(opts ...Option) (*Client, error) {
 	token := os.Getenv(TokenEnv)
 	if token == "" {
 		return nil, fmt.Errorf("onfido token not found in environmental variable `%s`", TokenEnv)
 	}
-	return NewClient(token), nil
+
+	var envOpts []Option
+	if v := os.Getenv(RegionEnv); v != "" {
+		region, err := parseRegion(v)
+		if err != nil {
+			return nil, err
+		}
+		envOpts = append(envOpts, WithRegion(region))
+	}
+	if v := os.Getenv(APIVersionEnv); v != "" {
+		envOpts = append(envOpts, WithAPIVersion(v))
+	}
+	if v := os.Getenv(EndpointEnv); v != "" && !configuresEndpoint(opts) {
+		envOpts = append(envOpts, WithBaseURL(v))
+	}
+	if v := os.Getenv(EnvironmentEnv); v != "" {
+		env, err := parseEnvironment(v)
+		if err != nil {
+			return nil, err
+		}
+		envOpts = append(envOpts, WithEnvironment(env))
+	}
+
+	client := NewClient(token, append(envOpts, opts...)...)
+	if err := client.CheckEnvironment(); err != nil {
+		return nil, err
+	}
+	return client, nil
 }
 
-// NewClient creates a new Onfido client.
-func NewClient(token string) *Client {
-	return &Client{
-		Endpoint:   DefaultEndpoint,
+// NewClient creates a new Onfido client, by default targeting
+// the EU region of API v3.6.
+func NewClient(token string, opts ...Option) *Client {
+	c := &Client{
 		HTTPClient: http.DefaultClient,
 		Token:      Token(token),
+		region:     DefaultRegion,
+		apiVersion: DefaultAPIVersion,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.Endpoint == "" {
+		c.Endpoint = c.region.Endpoint(c.apiVersion)
+	}
+	return c
+}
+
+// Region returns the region whose API host the client talks to. It is empty
+// when the endpoint isn't an Onfido regional host, e.g. one set with WithBaseURL.
+func (c *Client) Region() Region {
+	return endpointRegion(c.Endpoint)
+}
+
+// CurrentToken returns the token used for requests, taken from
+// the TokenSource when one is configured.
+func (c *Client) CurrentToken() (Token, error) {
+	if c.TokenSource != nil {
+		return c.TokenSource.Token()
+	}
+	return c.Token, nil
+}
+
+func (c *Client) userAgent() string {
+	ua := "Go-Onfido/" + ClientVersion
+	if c.userAgentSuffix != "" {
+		ua += " " + c.userAgentSuffix
 	}
+	return ua
 }
 
 func (c *Client) newRequest(method, uri string, body io.Reader) (*http.Request, error) {
@@ -99,14 +225,22 @@ func (c *Client) newRequest(method, uri string, body io.Reader) (*http.Request,
 		uri = c.Endpoint + uri
 	}
 
+	token, err := c.CurrentToken()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.checkTokenEnvironment(token); err != nil {
+		return nil, err
+	}
+
 	req, err := http.NewRequest(method, uri, body)
 	if err != nil {
 		return nil, err
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("User-Agent", "Go-Onfido/"+ClientVersion)
-	req.Header.Set("Authorization", "Token token="+c.Token.String())
+	req.Header.Set("User-Agent", c.userAgent())
+	req.Header.Set("Authorization", authorization(token))
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
@@ -115,24 +249,14 @@ func (c *Client) newRequest(method, uri string, body io.Reader) (*http.Request,
 }
 
 func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) (*http.Response, error) {
-	req = req.WithContext(ctx)
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.roundTrip(ctx, req)
 	if err != nil {
-		select {
-		case <-ctx.Done():
-			return nil, ctx.Err()
-		default:
-			return nil, err
-		}
+		return nil, err
 	}
 	if resp.Body != nil {
 		defer resp.Body.Close()
 	}
 
-	if c := resp.StatusCode; c < 200 || c > 299 {
-		return nil, handleResponseErr(resp)
-	}
-
 	if v != nil {
 		if w, ok := v.(io.Writer); ok {
 			_, err = io.Copy(w, resp.Body)
@@ -146,86 +270,114 @@ func (c *Client) do(ctx context.Context, req *http.Request, v interface{}) (*htt
 	return resp, err
 }
 
-func isJSONResponse(resp *http.Response) bool {
-	return strings.Contains(resp.Header.Get("Content-Type"), "application/json")
-}
-
-func handleResponseErr(resp *http.Response) error {
-	var onfidoErr Error
-	if resp.Body != nil && isJSONResponse(resp) {
-		defer resp.Body.Close()
-		if err := json.NewDecoder(resp.Body).Decode(&onfidoErr); err != nil {
-			return err
-		}
-	} else {
-		onfidoErr = Error{}
+// downloadTo GETs the file at uri, copying it to w as it is received.
+func (c *Client) downloadTo(ctx context.Context, uri string, w io.Writer) error {
+	req, err := c.newRequest("GET", uri, nil)
+	if err != nil {
+		return err
 	}
-	onfidoErr.Resp = resp
-	return &onfidoErr
-}
-
-type iter struct {
-	c       *Client
-	nextURL string
-	handler iterHandler
-
-	values []interface{}
-	cur    interface{}
-	err    error
+	_, err = c.do(ctx, req, w)
+	return err
 }
 
-type iterHandler func(body []byte) ([]interface{}, error)
-
-func (it *iter) Current() interface{} {
-	return it.cur
+// downloadStream GETs the file at uri, returning the response body for the caller to read and close.
+func (c *Client) downloadStream(ctx context.Context, uri string) (io.ReadCloser, error) {
+	req, err := c.newRequest("GET", uri, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.roundTrip(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
 }
 
-func (it *iter) Err() error {
-	return it.err
+func authorization(t Token) string {
+	return "Token token=" + t.Reveal()
 }
 
-func (it *iter) Next(ctx context.Context) bool {
-	if it.err != nil {
-		return false
-	}
-	if len(it.values) == 0 && it.nextURL != "" {
-		req, err := it.c.newRequest("GET", it.nextURL, nil)
+// send performs the request, pacing it with the client's RateLimiter and
+// retrying it according to the client's RetryPolicy. A request rejected as
+// unauthorized is retried once if the TokenSource comes up with a new token.
+func (c *Client) send(ctx context.Context, call *Call) (*http.Response, error) {
+	req := call.Request.WithContext(ctx)
+	refreshed := false
+	for attempt := 1; ; attempt++ {
+		call.Attempts = attempt
+		if err := c.RateLimiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := c.HTTPClient.Do(req)
 		if err != nil {
-			it.err = err
-			return false
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			default:
+			}
+		} else if resp.StatusCode == http.StatusTooManyRequests {
+			c.RateLimiter.throttled(resp)
+		} else if resp.StatusCode == http.StatusUnauthorized && !refreshed && canRewind(req) {
+			refreshed = true
+			if c.refreshToken(req) {
+				drain(resp)
+				if err := rewind(req); err != nil {
+					return nil, err
+				}
+				continue
+			}
 		}
 
-		var body bytes.Buffer
-		resp, err := it.c.do(ctx, req, &body)
-		if err != nil {
-			it.err = err
-			return false
+		wait, retry := c.RetryPolicy.delay(attempt, req, resp, err)
+		if !retry || exceedsDeadline(ctx, wait) {
+			return resp, err
 		}
-		if !isJSONResponse(resp) {
-			it.err = errors.New("non json response")
-			return false
+		drain(resp)
+		if err := sleep(ctx, wait); err != nil {
+			return nil, err
 		}
-
-		values, err := it.handler(body.Bytes())
-		if err != nil {
-			it.err = err
-			return false
+		if err := rewind(req); err != nil {
+			return nil, err
 		}
-		it.values = values
+	}
+}
 
-		links := linkheader.Parse(resp.Header.Get("Link"))
-		links = links.FilterByRel("next")
-		if len(links) > 0 {
-			it.nextURL = links[0].URL
-		} else {
-			it.nextURL = ""
-		}
+// refreshToken sets a new token on the request, reporting whether the TokenSource
+// provided one different from the token the request was sent with. Like any other
+// token, it must match the declared environment.
+func (c *Client) refreshToken(req *http.Request) bool {
+	if c.TokenSource == nil {
+		return false
+	}
+	if r, ok := c.TokenSource.(TokenRefresher); ok {
+		r.Refresh()
 	}
-	if len(it.values) == 0 {
+	token, err := c.TokenSource.Token()
+	if err != nil || authorization(token) == req.Header.Get("Authorization") {
 		return false
 	}
-
-	it.cur = it.values[0]
-	it.values = it.values[1:]
+	if err := c.checkTokenEnvironment(token); err != nil {
+		return false
+	}
+	req.Header.Set("Authorization", authorization(token))
 	return true
 }
+
+func isJSONResponse(resp *http.Response) bool {
+	return strings.Contains(resp.Header.Get("Content-Type"), "application/json")
+}
+
+// handleResponseErr builds the *Error of a failed response, bodies which
+// aren't a JSON error object only leaving the error with its status code.
+func handleResponseErr(resp *http.Response) error {
+	var onfidoErr Error
+	if resp.Body != nil && isJSONResponse(resp) {
+		defer resp.Body.Close()
+		if err := json.NewDecoder(resp.Body).Decode(&onfidoErr); err != nil {
+			onfidoErr = Error{}
+		}
+	}
+	onfidoErr.Resp = resp
+	onfidoErr.RequestID = resp.Header.Get(RequestIDHeader)
+	return &onfidoErr
+}
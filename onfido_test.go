@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"log/slog"
 	"net/http"
 	"os"
 	"strings"
@@ -61,6 +62,51 @@ func TestToken_IsProd(t *testing.T) {
 	}
 }
 
+func TestToken_Redacted(t *testing.T) {
+	tokens := []struct {
+		token    string
+		redacted string
+	}{
+		{"api_live.Wq2Td5wZyABJcDQ", "api_live.****JcDQ"},
+		{"api_sandbox.short", "api_sandbox.****"},
+		{"test_AbCdEfGhIjKl", "test_****IjKl"},
+		{"lk3j6323j442", "****j442"},
+		{"123", "****"},
+		{"", ""},
+	}
+
+	for _, expected := range tokens {
+		token := Token(expected.token)
+		assert.Equal(t, expected.redacted, token.String())
+		assert.Equal(t, expected.redacted, fmt.Sprintf("%v", token))
+		assert.Equal(t, expected.redacted, fmt.Sprintf("%s", token))
+		assert.Equal(t, fmt.Sprintf("%q", expected.redacted), fmt.Sprintf("%q", token))
+		assert.Equal(t, expected.token, token.Reveal())
+	}
+}
+
+func TestToken_RedactedInStructs(t *testing.T) {
+	client := NewClient("api_live.Wq2Td5wZyABJcDQ")
+
+	for _, out := range []string{
+		fmt.Sprintf("%+v", *client),
+		fmt.Sprintf("%#v", *client),
+		fmt.Sprintf("%v", WebhookRef{Token: "api_live.Wq2Td5wZyABJcDQ"}),
+	} {
+		assert.NotContains(t, out, "Wq2Td5wZyABJcDQ")
+	}
+
+	b, err := json.Marshal(WebhookRef{Token: "api_live.Wq2Td5wZyABJcDQ"})
+	assert.NoError(t, err)
+	assert.Contains(t, string(b), `"token":"api_live.****JcDQ"`)
+	assert.NotContains(t, string(b), "Wq2Td5wZyABJcDQ")
+
+	var buf bytes.Buffer
+	slog.New(slog.NewTextHandler(&buf, nil)).Info("client", "token", client.Token)
+	assert.Contains(t, buf.String(), "token=api_live.****JcDQ")
+	assert.NotContains(t, buf.String(), "Wq2Td5wZyABJcDQ")
+}
+
 func TestNewClientFromEnv_NoToken(t *testing.T) {
 	os.Setenv(TokenEnv, "")
 	if _, err := NewClientFromEnv(); err == nil {
@@ -77,7 +123,7 @@ func TestNewClientFromEnv_EnvSet(t *testing.T) {
 	if err != nil {
 		t.Fatal()
 	}
-	if client.Token.String() != expectedToken {
+	if client.Token.Reveal() != expectedToken {
 		t.Fatalf("expected token to be `%s` but got `%s`", expectedToken, client.Token)
 	}
 }
@@ -176,9 +222,33 @@ func TestDo_InvalidStatusCode_InvalidJsonParsed(t *testing.T) {
 	if err == nil {
 		t.Fatal("expected to see an error after the body was unable to be parsed as JSON")
 	}
-	if _, ok := err.(*Error); ok {
-		t.Fatal("json should not have been parsed")
+	onfidoErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected to see `*onfido.Error` but got %T", err)
+	}
+	assert.Equal(t, http.StatusBadGateway, onfidoErr.Resp.StatusCode)
+	assert.True(t, errors.Is(err, ErrServer))
+}
+
+func TestDo_InvalidStatusCode_UnexpectedJson(t *testing.T) {
+	resp := &http.Response{
+		Header:     make(map[string][]string),
+		StatusCode: http.StatusForbidden,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(`{"error":"Forbidden"}`)),
+	}
+	resp.Header.Add("Content-Type", "application/json")
+	resp.Header.Add(RequestIDHeader, "req-1")
+
+	client := NewClient("123")
+	client.HTTPClient = &stubbedHTTPClient{resp: resp}
+
+	_, err := client.do(context.Background(), &http.Request{}, &Applicant{})
+	onfidoErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected to see `*onfido.Error` but got %T", err)
 	}
+	assert.Equal(t, "req-1", onfidoErr.RequestID)
+	assert.True(t, errors.Is(err, ErrAuthorization))
 }
 
 func TestDo_InvalidStatusCode_JsonParsed(t *testing.T) {
@@ -0,0 +1,115 @@
+package onfido
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// Region represents an Onfido data region, each region being served from its own API host.
+// see https://documentation.onfido.com/#regions
+type Region string
+
+// Supported regions
+const (
+	RegionEU Region = "eu"
+	RegionUS Region = "us"
+	RegionCA Region = "ca"
+)
+
+// Defaults used when building the API endpoint
+const (
+	DefaultRegion     = RegionEU
+	DefaultAPIVersion = "3.6"
+)
+
+// Option configures a Client created by NewClient or NewClientFromEnv.
+type Option func(*Client)
+
+// WithRegion selects the region whose API host the client talks to.
+func WithRegion(r Region) Option {
+	return func(c *Client) {
+		c.region = r
+	}
+}
+
+// WithAPIVersion selects the API version, e.g. "3.6".
+func WithAPIVersion(v string) Option {
+	return func(c *Client) {
+		c.apiVersion = strings.TrimPrefix(v, "v")
+	}
+}
+
+// WithBaseURL overrides the API endpoint, taking precedence over WithRegion and WithAPIVersion.
+func WithBaseURL(u string) Option {
+	return func(c *Client) {
+		c.Endpoint = strings.TrimSuffix(u, "/")
+	}
+}
+
+// WithHTTPClient sets the HTTP client used to send requests.
+func WithHTTPClient(h HTTPRequester) Option {
+	return func(c *Client) {
+		c.HTTPClient = h
+	}
+}
+
+// WithUserAgentSuffix appends s to the User-Agent header sent with every request.
+func WithUserAgentSuffix(s string) Option {
+	return func(c *Client) {
+		c.userAgentSuffix = s
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry failed requests.
+func WithRetryPolicy(p *RetryPolicy) Option {
+	return func(c *Client) {
+		c.RetryPolicy = p
+	}
+}
+
+// WithRateLimiter sets the limiter waited on before every request.
+func WithRateLimiter(l *RateLimiter) Option {
+	return func(c *Client) {
+		c.RateLimiter = l
+	}
+}
+
+// Endpoint returns the API endpoint for the region and API version.
+func (r Region) Endpoint(apiVersion string) string {
+	return fmt.Sprintf("https://api.%s.onfido.com/v%s", r, strings.TrimPrefix(apiVersion, "v"))
+}
+
+// endpointRegion returns the region of an `https://api.<region>.onfido.com` endpoint.
+func endpointRegion(endpoint string) Region {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return ""
+	}
+	host := u.Hostname()
+	if !strings.HasPrefix(host, "api.") || !strings.HasSuffix(host, ".onfido.com") {
+		return ""
+	}
+	r, err := parseRegion(strings.TrimSuffix(strings.TrimPrefix(host, "api."), ".onfido.com"))
+	if err != nil {
+		return ""
+	}
+	return r
+}
+
+// configuresEndpoint reports whether the options set the region, API version or base URL.
+func configuresEndpoint(opts []Option) bool {
+	var c Client
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c.region != "" || c.apiVersion != "" || c.Endpoint != ""
+}
+
+func parseRegion(s string) (Region, error) {
+	switch r := Region(strings.ToLower(s)); r {
+	case RegionEU, RegionUS, RegionCA:
+		return r, nil
+	}
+	return "", fmt.Errorf("unsupported onfido region `%s`", s)
+}
@@ -0,0 +1,117 @@
+package onfido
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewClient_DefaultEndpoint(t *testing.T) {
+	client := NewClient("123")
+	assert.Equal(t, DefaultEndpoint, client.Endpoint)
+	assert.Equal(t, RegionEU, client.Region())
+	assert.Equal(t, http.DefaultClient, client.HTTPClient)
+}
+
+func TestNewClient_WithRegion(t *testing.T) {
+	regions := map[Region]string{
+		RegionEU: "https://api.eu.onfido.com/v3.6",
+		RegionUS: "https://api.us.onfido.com/v3.6",
+		RegionCA: "https://api.ca.onfido.com/v3.6",
+	}
+
+	for region, expected := range regions {
+		client := NewClient("123", WithRegion(region))
+		assert.Equal(t, expected, client.Endpoint)
+		assert.Equal(t, region, client.Region())
+	}
+}
+
+func TestNewClient_WithAPIVersion(t *testing.T) {
+	client := NewClient("123", WithRegion(RegionUS), WithAPIVersion("v3.5"))
+	assert.Equal(t, "https://api.us.onfido.com/v3.5", client.Endpoint)
+}
+
+func TestNewClient_WithBaseURL(t *testing.T) {
+	client := NewClient("123", WithBaseURL("http://localhost:8080/"), WithRegion(RegionCA))
+	assert.Equal(t, "http://localhost:8080", client.Endpoint)
+	assert.Equal(t, Region(""), client.Region())
+
+	client = NewClient("123", WithBaseURL("https://api.us.onfido.com/v3.5"))
+	assert.Equal(t, RegionUS, client.Region())
+}
+
+func TestNewClient_WithHTTPClient(t *testing.T) {
+	h := &stubbedHTTPClient{}
+	client := NewClient("123", WithHTTPClient(h))
+	assert.Equal(t, h, client.HTTPClient)
+}
+
+func TestNewClient_WithUserAgentSuffix(t *testing.T) {
+	client := NewClient("123", WithUserAgentSuffix("my-service/1.2"))
+	req, err := client.newRequest("GET", "/applicants", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Go-Onfido/"+ClientVersion+" my-service/1.2", req.Header.Get("User-Agent"))
+}
+
+func TestNewClientFromEnv_Region(t *testing.T) {
+	os.Setenv(TokenEnv, "123")
+	os.Setenv(RegionEnv, "US")
+	defer os.Setenv(TokenEnv, "")
+	defer os.Setenv(RegionEnv, "")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "https://api.us.onfido.com/v3.6", client.Endpoint)
+
+	client, err = NewClientFromEnv(WithRegion(RegionCA))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "https://api.ca.onfido.com/v3.6", client.Endpoint)
+}
+
+func TestNewClientFromEnv_Endpoint(t *testing.T) {
+	os.Setenv(TokenEnv, "123")
+	os.Setenv(EndpointEnv, "http://localhost:8080")
+	defer os.Setenv(TokenEnv, "")
+	defer os.Setenv(EndpointEnv, "")
+
+	client, err := NewClientFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "http://localhost:8080", client.Endpoint)
+
+	// the provided options take precedence over the environment
+	for _, opt := range []Option{WithRegion(RegionUS), WithAPIVersion("3.5"), WithBaseURL("http://localhost:9090")} {
+		client, err = NewClientFromEnv(opt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.NotEqual(t, "http://localhost:8080", client.Endpoint)
+	}
+	client, err = NewClientFromEnv(WithRegion(RegionUS))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "https://api.us.onfido.com/v3.6", client.Endpoint)
+	assert.Equal(t, RegionUS, client.Region())
+}
+
+func TestNewClientFromEnv_InvalidRegion(t *testing.T) {
+	os.Setenv(TokenEnv, "123")
+	os.Setenv(RegionEnv, "mars")
+	defer os.Setenv(TokenEnv, "")
+	defer os.Setenv(RegionEnv, "")
+
+	if _, err := NewClientFromEnv(); err == nil {
+		t.Fatal("expected an error for an unsupported region")
+	}
+}
@@ -0,0 +1,243 @@
+// Package otelonfido instruments an onfido.Client with OpenTelemetry tracing and metrics.
+//
+// Every API call, including list page fetches, is recorded as a client span named after
+// the operation together with latency, error and retry metrics. Only identifiers and
+// protocol level details are recorded; request bodies and query strings, which hold
+// personal data such as names, dates of birth or postcodes, never are.
+package otelonfido
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	onfido "github.com/uw-labs/go-onfido"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name used for the tracer and meter.
+const ScopeName = "github.com/uw-labs/go-onfido/otelonfido"
+
+// Attribute keys set on spans and metrics
+const (
+	OperationKey    = attribute.Key("onfido.operation")
+	RegionKey       = attribute.Key("onfido.region")
+	EnvironmentKey  = attribute.Key("onfido.environment")
+	ResourceTypeKey = attribute.Key("onfido.resource.type")
+	ResourceIDKey   = attribute.Key("onfido.resource.id")
+	ApplicantIDKey  = attribute.Key("onfido.applicant_id")
+	CheckIDKey      = attribute.Key("onfido.check_id")
+	RequestIDKey    = attribute.Key("onfido.request_id")
+	AttemptsKey     = attribute.Key("onfido.attempts")
+	ErrorClassKey   = attribute.Key("onfido.error.class")
+	MethodKey       = attribute.Key("http.request.method")
+	StatusCodeKey   = attribute.Key("http.response.status_code")
+)
+
+// Option configures the instrumentation.
+type Option func(*config)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// WithTracerProvider sets the tracer provider, the global one is used by default.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider, the global one is used by default.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// Instrument adds the tracing and metrics middleware to the client.
+func Instrument(client *onfido.Client, opts ...Option) error {
+	mw, err := Middleware(client, opts...)
+	if err != nil {
+		return err
+	}
+	client.Use(mw)
+	return nil
+}
+
+// Middleware returns an onfido.Middleware recording a span and metrics for every call made by client.
+func Middleware(client *onfido.Client, opts ...Option) (onfido.Middleware, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tracer := cfg.tracerProvider.Tracer(ScopeName)
+	meter := cfg.meterProvider.Meter(ScopeName)
+
+	duration, err := meter.Float64Histogram("onfido.client.duration",
+		metric.WithDescription("Duration of Onfido API calls, including retries"),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	errorCount, err := meter.Int64Counter("onfido.client.errors",
+		metric.WithDescription("Number of failed Onfido API calls by error class"),
+		metric.WithUnit("{call}"))
+	if err != nil {
+		return nil, err
+	}
+	retries, err := meter.Int64Counter("onfido.client.retries",
+		metric.WithDescription("Number of retried Onfido API requests"),
+		metric.WithUnit("{request}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(next onfido.RoundTripFunc) onfido.RoundTripFunc {
+		return func(ctx context.Context, call *onfido.Call) (*http.Response, error) {
+			common := []attribute.KeyValue{
+				OperationKey.String(call.Operation),
+				EnvironmentKey.String(environment(client)),
+			}
+			if region := client.Region(); region != "" {
+				common = append(common, RegionKey.String(string(region)))
+			}
+
+			ctx, span := tracer.Start(ctx, "onfido "+call.Operation,
+				trace.WithSpanKind(trace.SpanKindClient),
+				trace.WithAttributes(common...),
+				trace.WithAttributes(MethodKey.String(call.Request.Method)),
+				trace.WithAttributes(resourceAttributes(client, call.Request)...))
+			defer span.End()
+
+			start := time.Now()
+			resp, err := next(ctx, call)
+			elapsed := time.Since(start)
+
+			var result []attribute.KeyValue
+			if resp != nil {
+				result = append(result, StatusCodeKey.Int(resp.StatusCode))
+				if id := resp.Header.Get(onfido.RequestIDHeader); id != "" {
+					span.SetAttributes(RequestIDKey.String(id))
+				}
+			}
+			if err != nil {
+				class := ErrorClass(err)
+				result = append(result, ErrorClassKey.String(class))
+				// the error message may echo submitted values, only the class is recorded
+				span.SetStatus(codes.Error, class)
+			}
+			span.SetAttributes(result...)
+			span.SetAttributes(AttemptsKey.Int(call.Attempts))
+
+			attrs := metric.WithAttributes(append(common, result...)...)
+			duration.Record(ctx, elapsed.Seconds(), attrs)
+			if err != nil {
+				errorCount.Add(ctx, 1, attrs)
+			}
+			if call.Attempts > 1 {
+				retries.Add(ctx, int64(call.Attempts-1), metric.WithAttributes(common...))
+			}
+
+			return resp, err
+		}
+	}, nil
+}
+
+// ErrorClass returns the class of an error returned by the client, as recorded
+// in the onfido.error.class attribute.
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, onfido.ErrValidation):
+		return "validation"
+	case errors.Is(err, onfido.ErrAuthorization):
+		return "authorization"
+	case errors.Is(err, onfido.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, onfido.ErrRateLimited):
+		return "rate_limited"
+	case errors.Is(err, onfido.ErrConflict):
+		return "conflict"
+	case errors.Is(err, onfido.ErrServer):
+		return "server"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "cancelled"
+	}
+	var onfidoErr *onfido.Error
+	if errors.As(err, &onfidoErr) {
+		return "api"
+	}
+	return "transport"
+}
+
+func environment(client *onfido.Client) string {
+	token, err := client.CurrentToken()
+	switch {
+	case err != nil:
+		return "unknown"
+	case token.Prod():
+		return "live"
+	}
+	return "sandbox"
+}
+
+// resourceAttributes extracts the resource type and ID from the request path,
+// as well as the applicant and check IDs used to filter lists. No other part of
+// the query string is recorded as it may hold personal data.
+func resourceAttributes(client *onfido.Client, req *http.Request) []attribute.KeyValue {
+	path := req.URL.Path
+	if i := strings.Index(client.Endpoint, "://"); i >= 0 {
+		if j := strings.Index(client.Endpoint[i+3:], "/"); j >= 0 {
+			path = strings.TrimPrefix(path, client.Endpoint[i+3+j:])
+		}
+	}
+
+	var attrs []attribute.KeyValue
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if len(segments) > 0 && segments[0] != "" {
+		attrs = append(attrs, ResourceTypeKey.String(segments[0]))
+	}
+	if len(segments) > 1 && isID(segments[1]) {
+		attrs = append(attrs, ResourceIDKey.String(segments[1]))
+	}
+
+	q := req.URL.Query()
+	if id := q.Get("applicant_id"); isID(id) {
+		attrs = append(attrs, ApplicantIDKey.String(id))
+	}
+	if id := q.Get("check_id"); isID(id) {
+		attrs = append(attrs, CheckIDKey.String(id))
+	}
+	return attrs
+}
+
+// isID reports whether s looks like an Onfido resource ID (a UUID).
+func isID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case i == 8 || i == 13 || i == 18 || i == 23:
+			if r != '-' {
+				return false
+			}
+		case !strings.ContainsRune("0123456789abcdefABCDEF", r):
+			return false
+		}
+	}
+	return true
+}
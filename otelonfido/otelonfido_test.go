@@ -0,0 +1,208 @@
+package otelonfido_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	onfido "github.com/uw-labs/go-onfido"
+	"github.com/uw-labs/go-onfido/otelonfido"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const applicantID = "541d040b-89f8-444b-8921-16b1333bf1c6"
+
+func setup(t *testing.T, handler http.HandlerFunc) (*onfido.Client, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+
+	// talk to the test server while keeping the endpoint of the US region
+	target, err := url.Parse(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	transport := roundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		r.URL.Scheme, r.URL.Host = target.Scheme, target.Host
+		return http.DefaultTransport.RoundTrip(r)
+	})
+
+	client := onfido.NewClient("api_sandbox.123", onfido.WithRegion(onfido.RegionUS),
+		onfido.WithHTTPClient(&http.Client{Transport: transport}))
+	err = otelonfido.Instrument(client,
+		otelonfido.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))),
+		otelonfido.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client, recorder, reader
+}
+
+type roundTripperFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+func attrs(kvs []attribute.KeyValue) map[attribute.Key]attribute.Value {
+	m := make(map[attribute.Key]attribute.Value, len(kvs))
+	for _, kv := range kvs {
+		m[kv.Key] = kv.Value
+	}
+	return m
+}
+
+func TestInstrument_Span(t *testing.T) {
+	client, recorder, _ := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set(onfido.RequestIDHeader, "req-1")
+		_, _ = w.Write([]byte(`{"id":"` + applicantID + `","first_name":"Rob"}`))
+	})
+
+	if _, err := client.GetApplicant(context.Background(), applicantID); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "onfido GetApplicant" {
+		t.Fatalf("unexpected span name %q", span.Name())
+	}
+
+	got := attrs(span.Attributes())
+	expected := map[attribute.Key]string{
+		otelonfido.OperationKey:    "GetApplicant",
+		otelonfido.RegionKey:       "us",
+		otelonfido.EnvironmentKey:  "sandbox",
+		otelonfido.ResourceTypeKey: "applicants",
+		otelonfido.ResourceIDKey:   applicantID,
+		otelonfido.RequestIDKey:    "req-1",
+		otelonfido.MethodKey:       "GET",
+	}
+	for k, v := range expected {
+		if got[k].AsString() != v {
+			t.Errorf("expected attribute %s to be %q, got %q", k, v, got[k].Emit())
+		}
+	}
+	if got[otelonfido.StatusCodeKey].AsInt64() != http.StatusOK {
+		t.Errorf("unexpected status code attribute %s", got[otelonfido.StatusCodeKey].Emit())
+	}
+}
+
+func TestInstrument_NoPII(t *testing.T) {
+	client, recorder, _ := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = w.Write([]byte(`{"error":{"type":"validation_error","message":"dob 1990-01-31 is invalid"}}`))
+	})
+
+	_, err := client.CreateApplicant(context.Background(), onfido.Applicant{
+		FirstName: "Rob",
+		LastName:  "Crowe",
+		DOB:       "1990-01-31",
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	it := client.PickAddresses("SW4 6EH")
+	for it.Next(context.Background()) {
+	}
+
+	for _, span := range recorder.Ended() {
+		for _, kv := range span.Attributes() {
+			for _, pii := range []string{"Rob", "Crowe", "1990-01-31", "SW4"} {
+				if strings.Contains(kv.Value.Emit(), pii) {
+					t.Errorf("span %s leaks %q in attribute %s", span.Name(), pii, kv.Key)
+				}
+			}
+		}
+		if strings.Contains(span.Status().Description, "1990") {
+			t.Errorf("span %s leaks the error message", span.Name())
+		}
+	}
+
+	span := recorder.Ended()[0]
+	if span.Status().Code != codes.Error {
+		t.Fatalf("expected an error status, got %s", span.Status().Code)
+	}
+	if got := attrs(span.Attributes())[otelonfido.ErrorClassKey].AsString(); got != "validation" {
+		t.Fatalf("expected a validation error class, got %q", got)
+	}
+}
+
+func TestInstrument_Metrics(t *testing.T) {
+	var calls int
+	client, _, reader := setup(t, func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	})
+	policy := onfido.DefaultRetryPolicy()
+	policy.BaseBackoff = 0
+	client.RetryPolicy = policy
+
+	if _, err := client.GetCheck(context.Background(), applicantID); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+
+	found := map[string]bool{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			found[m.Name] = true
+			switch data := m.Data.(type) {
+			case metricdata.Sum[int64]:
+				for _, dp := range data.DataPoints {
+					if m.Name == "onfido.client.retries" && dp.Value != 1 {
+						t.Errorf("expected 1 retry, got %d", dp.Value)
+					}
+					if m.Name == "onfido.client.errors" {
+						class, _ := dp.Attributes.Value(otelonfido.ErrorClassKey)
+						if class.AsString() != "not_found" {
+							t.Errorf("expected a not_found error class, got %q", class.AsString())
+						}
+					}
+				}
+			case metricdata.Histogram[float64]:
+				if len(data.DataPoints) != 1 || data.DataPoints[0].Count != 1 {
+					t.Errorf("expected a single duration measurement")
+				}
+			}
+		}
+	}
+	for _, name := range []string{"onfido.client.duration", "onfido.client.errors", "onfido.client.retries"} {
+		if !found[name] {
+			t.Errorf("metric %s was not recorded", name)
+		}
+	}
+}
+
+func TestErrorClass(t *testing.T) {
+	if got := otelonfido.ErrorClass(context.Canceled); got != "cancelled" {
+		t.Errorf("unexpected class %q", got)
+	}
+	if got := otelonfido.ErrorClass(&onfido.Error{Resp: &http.Response{StatusCode: http.StatusTooManyRequests}}); got != "rate_limited" {
+		t.Errorf("unexpected class %q", got)
+	}
+}
@@ -0,0 +1,112 @@
+package onfido
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RateLimiter is a token bucket limiting the rate at which a Client sends requests.
+// It is safe for concurrent use and can be shared between clients using the same
+// Onfido account.
+type RateLimiter struct {
+	// OnThrottled is called whenever Onfido rejects a request with 429 Too Many Requests,
+	// allowing the limiter to adapt, e.g. by calling SetRate.
+	OnThrottled func(l *RateLimiter, resp *http.Response)
+
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+// NewRateLimiter creates a limiter allowing rate requests per second on average,
+// with bursts of up to burst requests.
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Rate returns the current number of requests allowed per second.
+func (l *RateLimiter) Rate() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.rate
+}
+
+// SetRate changes the number of requests allowed per second.
+// A rate of zero or less disables limiting.
+func (l *RateLimiter) SetRate(rate float64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.refill(time.Now())
+	l.rate = rate
+}
+
+// Wait blocks until a request may be sent or the context is done.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	if l.rate <= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	l.refill(time.Now())
+	l.tokens--
+	if l.tokens >= 0 {
+		l.mu.Unlock()
+		return nil
+	}
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.mu.Unlock()
+
+	if err := sleep(ctx, wait); err != nil {
+		// hand back the token we reserved, nobody used it
+		l.mu.Lock()
+		l.tokens++
+		l.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// refill adds the tokens accumulated since the last refill, l.mu must be held.
+func (l *RateLimiter) refill(now time.Time) {
+	if elapsed := now.Sub(l.last); elapsed > 0 && l.rate > 0 {
+		l.tokens += elapsed.Seconds() * l.rate
+		if l.tokens > l.burst {
+			l.tokens = l.burst
+		}
+	}
+	l.last = now
+}
+
+func (l *RateLimiter) throttled(resp *http.Response) {
+	if l != nil && l.OnThrottled != nil {
+		l.OnThrottled(l, resp)
+	}
+}
+
+// SlowDownOnThrottle returns an OnThrottled hook which multiplies the limiter
+// rate by factor every time a request is throttled, without going below min.
+func SlowDownOnThrottle(factor, min float64) func(*RateLimiter, *http.Response) {
+	return func(l *RateLimiter, _ *http.Response) {
+		rate := l.Rate() * factor
+		if rate < min {
+			rate = min
+		}
+		l.SetRate(rate)
+	}
+}
@@ -0,0 +1,91 @@
+package onfido
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRateLimiter_Wait(t *testing.T) {
+	l := NewRateLimiter(50, 2)
+	ctx := context.Background()
+
+	start := time.Now()
+	for i := 0; i < 4; i++ {
+		if err := l.Wait(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// two requests are allowed by the burst, the other two need 20ms each
+	assert.True(t, time.Since(start) >= 35*time.Millisecond)
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	l := NewRateLimiter(0.1, 1)
+	assert.NoError(t, l.Wait(context.Background()))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.Equal(t, context.DeadlineExceeded, l.Wait(ctx))
+}
+
+func TestRateLimiter_Disabled(t *testing.T) {
+	var l *RateLimiter
+	assert.NoError(t, l.Wait(context.Background()))
+
+	l = NewRateLimiter(0, 1)
+	for i := 0; i < 100; i++ {
+		assert.NoError(t, l.Wait(context.Background()))
+	}
+}
+
+func TestRateLimiter_SlowDownOnThrottle(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123")
+	client.Endpoint = srv.URL
+	client.RateLimiter = NewRateLimiter(100, 10)
+	client.RateLimiter.OnThrottled = SlowDownOnThrottle(0.5, 30)
+
+	_, err := client.GetApplicant(context.Background(), "123")
+	assert.Error(t, err)
+	assert.Equal(t, float64(50), client.RateLimiter.Rate())
+
+	_, err = client.GetApplicant(context.Background(), "123")
+	assert.Error(t, err)
+	assert.Equal(t, float64(30), client.RateLimiter.Rate())
+}
+
+func TestRateLimiter_ListPagesWait(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Get("page") == "" {
+			w.Header().Set("Link", "<"+srv.URL+"/applicants?page=2>; rel=\"next\"")
+		}
+		_, wErr := w.Write([]byte(`{"applicants":[{"id":"1"}]}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123")
+	client.Endpoint = srv.URL
+	client.RateLimiter = NewRateLimiter(20, 1)
+
+	start := time.Now()
+	it := client.ListApplicants()
+	var count int
+	for it.Next(context.Background()) {
+		count++
+	}
+	assert.NoError(t, it.Err())
+	assert.Equal(t, 2, count)
+	assert.True(t, time.Since(start) >= 45*time.Millisecond)
+}
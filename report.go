@@ -2,7 +2,7 @@ package onfido
 
 import (
 	"context"
-	"encoding/json"
+	"net/url"
 	"time"
 )
 
@@ -77,7 +77,7 @@ func (c *Client) GetReport(ctx context.Context, id string) (*Report, error) {
 	}
 
 	var resp Report
-	_, err = c.do(ctx, req, &resp)
+	_, err = c.do(withOperation(ctx, "GetReport"), req, &resp)
 	return &resp, err
 }
 
@@ -89,7 +89,7 @@ func (c *Client) ResumeReport(ctx context.Context, id string) error {
 		return err
 	}
 
-	_, err = c.do(ctx, req, nil)
+	_, err = c.do(withOperation(ctx, "ResumeReport"), req, nil)
 	return err
 }
 
@@ -101,39 +101,26 @@ func (c *Client) CancelReport(ctx context.Context, id string) error {
 		return err
 	}
 
-	_, err = c.do(ctx, req, nil)
+	_, err = c.do(withOperation(ctx, "CancelReport"), req, nil)
 	return err
 }
 
-// ReportIter represents a document iterator
+// ReportIter represents a report iterator.
+// It only adds the deprecated Report accessor to Iter[*Report].
 type ReportIter struct {
-	*iter
+	*Iter[*Report]
 }
 
 // Report returns the current item in the iterator as a Report.
+//
+// Deprecated: use Current.
 func (i *ReportIter) Report() *Report {
-	return i.Current().(*Report)
+	return i.Current()
 }
 
 // ListReports retrieves the list of reports for the provided check.
 // see https://documentation.onfido.com/?shell#list-reports
-func (c *Client) ListReports(checkID string) *ReportIter {
-	handler := func(body []byte) ([]interface{}, error) {
-		var r Reports
-		if err := json.Unmarshal(body, &r); err != nil {
-			return nil, err
-		}
-
-		values := make([]interface{}, len(r.Reports))
-		for i, v := range r.Reports {
-			values[i] = v
-		}
-		return values, nil
-	}
-
-	return &ReportIter{&iter{
-		c:       c,
-		nextURL: "/reports?check_id=" + checkID,
-		handler: handler,
-	}}
+func (c *Client) ListReports(checkID string, opts ...ListOption) *ReportIter {
+	params := url.Values{"check_id": {checkID}}
+	return &ReportIter{newIter[*Report](c, "ListReports", "reports", listURL("/reports", params, opts))}
 }
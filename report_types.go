@@ -0,0 +1,429 @@
+package onfido
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// ErrReportName means a typed report accessor was called on a report of another kind
+var ErrReportName = errors.New("unexpected report name")
+
+// decodeAs decodes the breakdown and properties of the report into the typed models,
+// provided the report has one of the names.
+func (r *Report) decodeAs(kind string, names []ReportName, breakdown, properties interface{}) error {
+	found := false
+	for _, name := range names {
+		found = found || r.Name == name
+	}
+	if !found {
+		return fmt.Errorf("%w: %s report %s is not a %s report", ErrReportName, r.Name, r.ID, kind)
+	}
+	if err := remarshal(r.Breakdown, breakdown); err != nil {
+		return fmt.Errorf("decoding %s report breakdown: %w", kind, err)
+	}
+	if err := remarshal(r.Properties, properties); err != nil {
+		return fmt.Errorf("decoding %s report properties: %w", kind, err)
+	}
+	return nil
+}
+
+// remarshal decodes the raw breakdown or properties of a report into v.
+func remarshal(raw, v interface{}) error {
+	b, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// Document reports
+
+// DocumentReport is a document report with its breakdown and properties typed.
+// The raw ones remain available from the embedded Report.
+type DocumentReport struct {
+	*Report
+	Breakdown  DocumentBreakdown
+	Properties DocumentProperties
+}
+
+// DocumentBreakdown is the breakdown of a document report.
+type DocumentBreakdown struct {
+	DataComparison      *TypedBreakdown[DocumentDataComparison]     `json:"data_comparison,omitempty"`
+	DataValidation      *TypedBreakdown[DocumentDataValidation]     `json:"data_validation,omitempty"`
+	DataConsistency     *TypedBreakdown[DocumentDataConsistency]    `json:"data_consistency,omitempty"`
+	ImageIntegrity      *TypedBreakdown[DocumentImageIntegrity]     `json:"image_integrity,omitempty"`
+	VisualAuthenticity  *TypedBreakdown[DocumentVisualAuthenticity] `json:"visual_authenticity,omitempty"`
+	PoliceRecord        *Breakdown                                  `json:"police_record,omitempty"`
+	CompromisedDocument *Breakdown                                  `json:"compromised_document,omitempty"`
+	AgeValidation       *TypedBreakdown[DocumentAgeValidation]      `json:"age_validation,omitempty"`
+	IssuingAuthority    *TypedBreakdown[DocumentIssuingAuthority]   `json:"issuing_authority,omitempty"`
+}
+
+// DocumentDataComparison holds the sub-breakdowns of the data comparison breakdown,
+// comparing the document data with the applicant details.
+type DocumentDataComparison struct {
+	IssuingCountry  *SubBreakdown `json:"issuing_country,omitempty"`
+	Gender          *SubBreakdown `json:"gender,omitempty"`
+	DateOfExpiry    *SubBreakdown `json:"date_of_expiry,omitempty"`
+	LastName        *SubBreakdown `json:"last_name,omitempty"`
+	DocumentType    *SubBreakdown `json:"document_type,omitempty"`
+	DocumentNumbers *SubBreakdown `json:"document_numbers,omitempty"`
+	FirstName       *SubBreakdown `json:"first_name,omitempty"`
+	DateOfBirth     *SubBreakdown `json:"date_of_birth,omitempty"`
+}
+
+// DocumentDataValidation holds the sub-breakdowns of the data validation breakdown,
+// validating the format of the document data.
+type DocumentDataValidation struct {
+	DocumentExpiration *SubBreakdown `json:"document_expiration,omitempty"`
+	Gender             *SubBreakdown `json:"gender,omitempty"`
+	DateOfBirth        *SubBreakdown `json:"date_of_birth,omitempty"`
+	ExpiryDate         *SubBreakdown `json:"expiry_date,omitempty"`
+	MRZ                *SubBreakdown `json:"mrz,omitempty"`
+	DocumentNumbers    *SubBreakdown `json:"document_numbers,omitempty"`
+	Barcode            *SubBreakdown `json:"barcode,omitempty"`
+}
+
+// DocumentDataConsistency holds the sub-breakdowns of the data consistency breakdown,
+// comparing the data extracted from the different parts of the document.
+type DocumentDataConsistency struct {
+	DateOfExpiry               *SubBreakdown `json:"date_of_expiry,omitempty"`
+	DocumentNumbers            *SubBreakdown `json:"document_numbers,omitempty"`
+	IssuingCountry             *SubBreakdown `json:"issuing_country,omitempty"`
+	DocumentType               *SubBreakdown `json:"document_type,omitempty"`
+	DateOfBirth                *SubBreakdown `json:"date_of_birth,omitempty"`
+	Gender                     *SubBreakdown `json:"gender,omitempty"`
+	FirstName                  *SubBreakdown `json:"first_name,omitempty"`
+	LastName                   *SubBreakdown `json:"last_name,omitempty"`
+	Nationality                *SubBreakdown `json:"nationality,omitempty"`
+	MultipleDataSourcesPresent *SubBreakdown `json:"multiple_data_sources_present,omitempty"`
+}
+
+// DocumentImageIntegrity holds the sub-breakdowns of the image integrity breakdown,
+// telling whether the document image was of sufficient quality to be processed.
+type DocumentImageIntegrity struct {
+	ImageQuality              *SubBreakdown `json:"image_quality,omitempty"`
+	SupportedDocument         *SubBreakdown `json:"supported_document,omitempty"`
+	ColourPicture             *SubBreakdown `json:"colour_picture,omitempty"`
+	ConclusiveDocumentQuality *SubBreakdown `json:"conclusive_document_quality,omitempty"`
+}
+
+// DocumentVisualAuthenticity holds the sub-breakdowns of the visual authenticity breakdown,
+// looking for signs of a fraudulent document.
+type DocumentVisualAuthenticity struct {
+	Fonts                   *SubBreakdown `json:"fonts,omitempty"`
+	PictureFaceIntegrity    *SubBreakdown `json:"picture_face_integrity,omitempty"`
+	Template                *SubBreakdown `json:"template,omitempty"`
+	SecurityFeatures        *SubBreakdown `json:"security_features,omitempty"`
+	OriginalDocumentPresent *SubBreakdown `json:"original_document_present,omitempty"`
+	DigitalTampering        *SubBreakdown `json:"digital_tampering,omitempty"`
+	FaceDetection           *SubBreakdown `json:"face_detection,omitempty"`
+	Other                   *SubBreakdown `json:"other,omitempty"`
+}
+
+// DocumentAgeValidation holds the sub-breakdowns of the age validation breakdown,
+// checking the applicant is older than the configured minimum age.
+type DocumentAgeValidation struct {
+	MinimumAcceptedAge *SubBreakdown `json:"minimum_accepted_age,omitempty"`
+}
+
+// DocumentIssuingAuthority holds the sub-breakdowns of the issuing authority breakdown,
+// verifying the document chip with NFC.
+type DocumentIssuingAuthority struct {
+	NFCActiveAuthentication  *SubBreakdown `json:"nfc_active_authentication,omitempty"`
+	NFCPassiveAuthentication *SubBreakdown `json:"nfc_passive_authentication,omitempty"`
+}
+
+// DocumentNumber is a number extracted from a document.
+type DocumentNumber struct {
+	Type  string `json:"type,omitempty"`
+	Value string `json:"value,omitempty"`
+}
+
+// DocumentProperties holds the data extracted from the document, dates being formatted as YYYY-MM-DD.
+type DocumentProperties struct {
+	DocumentType    DocumentType     `json:"document_type,omitempty"`
+	DocumentNumbers []DocumentNumber `json:"document_numbers,omitempty"`
+	IssuingCountry  string           `json:"issuing_country,omitempty"`
+	IssuingState    string           `json:"issuing_state,omitempty"`
+	IssuingDate     string           `json:"issuing_date,omitempty"`
+	DateOfExpiry    string           `json:"date_of_expiry,omitempty"`
+	FirstName       string           `json:"first_name,omitempty"`
+	MiddleName      string           `json:"middle_name,omitempty"`
+	LastName        string           `json:"last_name,omitempty"`
+	DateOfBirth     string           `json:"date_of_birth,omitempty"`
+	PlaceOfBirth    string           `json:"place_of_birth,omitempty"`
+	Gender          string           `json:"gender,omitempty"`
+	Nationality     string           `json:"nationality,omitempty"`
+	PersonalNumber  string           `json:"personal_number,omitempty"`
+	Address         string           `json:"address,omitempty"`
+	MRZLine1        string           `json:"mrz_line1,omitempty"`
+	MRZLine2        string           `json:"mrz_line2,omitempty"`
+	MRZLine3        string           `json:"mrz_line3,omitempty"`
+}
+
+// documentReportNames are the names of the document report variants.
+var documentReportNames = []ReportName{
+	ReportNameDocument,
+	ReportNameDocumentVideo,
+	ReportNameDocumentWithAddressInformation,
+	ReportNameDocumentVideoWithAddressInformation,
+	ReportNameDocumentWithDrivingLicenceInformation,
+	ReportNameDocumentWithDriverVerification,
+}
+
+// AsDocument returns the typed breakdown and properties of a document report.
+// It fails with ErrReportName for other reports.
+func (r *Report) AsDocument() (*DocumentReport, error) {
+	d := &DocumentReport{Report: r}
+	if err := r.decodeAs("document", documentReportNames, &d.Breakdown, &d.Properties); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Facial similarity reports
+
+// FacialSimilarityReport is a facial similarity report with its breakdown and properties typed.
+// The raw ones remain available from the embedded Report.
+type FacialSimilarityReport struct {
+	*Report
+	Breakdown  FacialSimilarityBreakdown
+	Properties FacialSimilarityProperties
+}
+
+// FacialSimilarityBreakdown is the breakdown of a facial similarity report.
+type FacialSimilarityBreakdown struct {
+	FaceComparison     *TypedBreakdown[FacialSimilarityFaceComparison]     `json:"face_comparison,omitempty"`
+	ImageIntegrity     *TypedBreakdown[FacialSimilarityImageIntegrity]     `json:"image_integrity,omitempty"`
+	VisualAuthenticity *TypedBreakdown[FacialSimilarityVisualAuthenticity] `json:"visual_authenticity,omitempty"`
+}
+
+// FacialSimilarityFaceComparison holds the sub-breakdowns of the face comparison breakdown.
+type FacialSimilarityFaceComparison struct {
+	FaceMatch *SubBreakdown `json:"face_match,omitempty"`
+}
+
+// FacialSimilarityImageIntegrity holds the sub-breakdowns of the image integrity breakdown.
+type FacialSimilarityImageIntegrity struct {
+	FaceDetected    *SubBreakdown `json:"face_detected,omitempty"`
+	SourceIntegrity *SubBreakdown `json:"source_integrity,omitempty"`
+}
+
+// FacialSimilarityVisualAuthenticity holds the sub-breakdowns of the visual authenticity breakdown.
+type FacialSimilarityVisualAuthenticity struct {
+	LivenessDetected  *SubBreakdown `json:"liveness_detected,omitempty"`
+	SpoofingDetection *SubBreakdown `json:"spoofing_detection,omitempty"`
+}
+
+// FacialSimilarityProperties holds the properties of a facial similarity report.
+type FacialSimilarityProperties struct {
+	// Score is the face match score, between 0 and 1.
+	Score *float64 `json:"score,omitempty"`
+}
+
+// facialSimilarityReportNames are the names of the facial similarity report variants.
+var facialSimilarityReportNames = []ReportName{
+	ReportNameFacialSimilarityPhoto,
+	ReportNameFacialSimilarityPhotoFullyAuto,
+	ReportNameFacialSimilarityVideo,
+	ReportNameFacialSimilarityMotion,
+}
+
+// AsFacialSimilarity returns the typed breakdown and properties of a facial similarity
+// photo, video or motion report. It fails with ErrReportName for other reports.
+func (r *Report) AsFacialSimilarity() (*FacialSimilarityReport, error) {
+	f := &FacialSimilarityReport{Report: r}
+	if err := r.decodeAs("facial similarity", facialSimilarityReportNames, &f.Breakdown, &f.Properties); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Watchlist reports
+
+// WatchlistReport is a watchlist report with its breakdown and properties typed.
+// The raw ones remain available from the embedded Report.
+type WatchlistReport struct {
+	*Report
+	Breakdown  WatchlistBreakdown
+	Properties WatchlistProperties
+}
+
+// WatchlistBreakdown is the breakdown of a watchlist report, the lists searched depending on the report variant.
+type WatchlistBreakdown struct {
+	Sanction                   *Breakdown `json:"sanction,omitempty"`
+	PoliticallyExposedPerson   *Breakdown `json:"politically_exposed_person,omitempty"`
+	LegalAndRegulatoryWarnings *Breakdown `json:"legal_and_regulatory_warnings,omitempty"`
+	AdverseMedia               *Breakdown `json:"adverse_media,omitempty"`
+	MonitoredLists             *Breakdown `json:"monitored_lists,omitempty"`
+}
+
+// WatchlistRecord is an entry of a watchlist matching the applicant.
+type WatchlistRecord struct {
+	FullName    string            `json:"full_name,omitempty"`
+	Position    string            `json:"position,omitempty"`
+	DateOfBirth []string          `json:"date_of_birth,omitempty"`
+	Keywords    []string          `json:"keywords,omitempty"`
+	Sources     []WatchlistSource `json:"sources,omitempty"`
+	Aliases     []WatchlistAlias  `json:"aliases,omitempty"`
+}
+
+// WatchlistSource is a source a watchlist record comes from.
+type WatchlistSource struct {
+	Name string `json:"source_name,omitempty"`
+	URL  string `json:"source_url,omitempty"`
+}
+
+// WatchlistAlias is another name a watchlist record is known by.
+type WatchlistAlias struct {
+	Name string `json:"alias_name,omitempty"`
+	Type string `json:"alias_type,omitempty"`
+}
+
+// WatchlistProperties holds the records matching the applicant.
+type WatchlistProperties struct {
+	Records []WatchlistRecord `json:"records,omitempty"`
+}
+
+// watchlistReportNames are the names of the watchlist report variants.
+var watchlistReportNames = []ReportName{
+	ReportNameWatchlistEnhanced,
+	ReportNameWatchlistAML,
+	ReportNameWatchlistStandard,
+	ReportNameWatchlistPepsOnly,
+	ReportNameWatchlistSanctionsOnly,
+}
+
+// AsWatchlist returns the typed breakdown and properties of a watchlist report.
+// It fails with ErrReportName for other reports.
+func (r *Report) AsWatchlist() (*WatchlistReport, error) {
+	w := &WatchlistReport{Report: r}
+	if err := r.decodeAs("watchlist", watchlistReportNames, &w.Breakdown, &w.Properties); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+// Proof of address reports
+
+// ProofOfAddressReport is a proof of address report with its breakdown and properties typed.
+// The raw ones remain available from the embedded Report.
+type ProofOfAddressReport struct {
+	*Report
+	Breakdown  ProofOfAddressBreakdown
+	Properties ProofOfAddressProperties
+}
+
+// ProofOfAddressBreakdown is the breakdown of a proof of address report.
+type ProofOfAddressBreakdown struct {
+	DataComparison         *TypedBreakdown[ProofOfAddressDataComparison]         `json:"data_comparison,omitempty"`
+	DocumentClassification *TypedBreakdown[ProofOfAddressDocumentClassification] `json:"document_classification,omitempty"`
+	ImageIntegrity         *TypedBreakdown[ProofOfAddressImageIntegrity]         `json:"image_integrity,omitempty"`
+}
+
+// ProofOfAddressDataComparison holds the sub-breakdowns of the data comparison breakdown.
+type ProofOfAddressDataComparison struct {
+	Address   *SubBreakdown `json:"address,omitempty"`
+	FirstName *SubBreakdown `json:"first_name,omitempty"`
+	LastName  *SubBreakdown `json:"last_name,omitempty"`
+}
+
+// ProofOfAddressDocumentClassification holds the sub-breakdowns of the document classification breakdown.
+type ProofOfAddressDocumentClassification struct {
+	IssueDate         *SubBreakdown `json:"issue_date,omitempty"`
+	SummaryPeriod     *SubBreakdown `json:"summary_period,omitempty"`
+	SupportedDocument *SubBreakdown `json:"supported_document,omitempty"`
+}
+
+// ProofOfAddressImageIntegrity holds the sub-breakdowns of the image integrity breakdown.
+type ProofOfAddressImageIntegrity struct {
+	ImageQuality *SubBreakdown `json:"image_quality,omitempty"`
+}
+
+// ProofOfAddressProperties holds the data extracted from the proof of address document.
+type ProofOfAddressProperties struct {
+	DocumentType       string `json:"document_type,omitempty"`
+	Issuer             string `json:"issuer,omitempty"`
+	IssueDate          string `json:"issue_date,omitempty"`
+	SummaryPeriodStart string `json:"summary_period_start,omitempty"`
+	SummaryPeriodEnd   string `json:"summary_period_end,omitempty"`
+	FirstNames         string `json:"first_names,omitempty"`
+	LastNames          string `json:"last_names,omitempty"`
+	Address            string `json:"address,omitempty"`
+}
+
+// AsProofOfAddress returns the typed breakdown and properties of a proof of address report.
+// It fails with ErrReportName for other reports.
+func (r *Report) AsProofOfAddress() (*ProofOfAddressReport, error) {
+	p := &ProofOfAddressReport{Report: r}
+	if err := r.decodeAs("proof of address", []ReportName{ReportNameProofOfAddress}, &p.Breakdown, &p.Properties); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Device intelligence reports
+
+// DeviceIntelligenceReport is a device intelligence report with its breakdown and properties typed.
+// The raw ones remain available from the embedded Report.
+type DeviceIntelligenceReport struct {
+	*Report
+	Breakdown  DeviceIntelligenceBreakdown
+	Properties DeviceIntelligenceProperties
+}
+
+// DeviceIntelligenceBreakdown is the breakdown of a device intelligence report.
+type DeviceIntelligenceBreakdown struct {
+	Device *TypedBreakdown[DeviceIntelligenceDevice] `json:"device,omitempty"`
+}
+
+// DeviceIntelligenceDevice holds the sub-breakdowns of the device breakdown.
+type DeviceIntelligenceDevice struct {
+	ApplicationAuthenticity *SubBreakdown `json:"application_authenticity,omitempty"`
+	DeviceIntegrity         *SubBreakdown `json:"device_integrity,omitempty"`
+	DeviceReputation        *SubBreakdown `json:"device_reputation,omitempty"`
+}
+
+// DeviceIntelligenceProperties holds the details of the device and connection the applicant used.
+type DeviceIntelligenceProperties struct {
+	Device      DeviceProperties      `json:"device"`
+	IP          IPProperties          `json:"ip"`
+	Geolocation GeolocationProperties `json:"geolocation"`
+}
+
+// DeviceProperties describes the device the applicant used.
+type DeviceProperties struct {
+	SDKVersion         string `json:"sdk_version,omitempty"`
+	SDKSource          string `json:"sdk_source,omitempty"`
+	AuthenticationType string `json:"authentication_type,omitempty"`
+	RawModel           string `json:"raw_model,omitempty"`
+	OS                 string `json:"os,omitempty"`
+	Browser            string `json:"browser,omitempty"`
+	Emulator           bool   `json:"emulator,omitempty"`
+	FingerprintReuse   int    `json:"fingerprint_reuse,omitempty"`
+}
+
+// IPProperties describes the IP address the applicant connected from.
+type IPProperties struct {
+	Address      string `json:"address,omitempty"`
+	IPReputation string `json:"ip_reputation,omitempty"`
+}
+
+// GeolocationProperties is the location of the IP address the applicant connected from.
+type GeolocationProperties struct {
+	City    string `json:"city,omitempty"`
+	Region  string `json:"region,omitempty"`
+	Country string `json:"country,omitempty"`
+}
+
+// AsDeviceIntelligence returns the typed breakdown and properties of a device intelligence report.
+// It fails with ErrReportName for other reports.
+func (r *Report) AsDeviceIntelligence() (*DeviceIntelligenceReport, error) {
+	d := &DeviceIntelligenceReport{Report: r}
+	if err := r.decodeAs("device intelligence", []ReportName{ReportNameDeviceIntelligence}, &d.Breakdown, &d.Properties); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
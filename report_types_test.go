@@ -0,0 +1,170 @@
+package onfido_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	onfido "github.com/uw-labs/go-onfido"
+)
+
+func decodeReport(t *testing.T, data string) *onfido.Report {
+	var r onfido.Report
+	if err := json.Unmarshal([]byte(data), &r); err != nil {
+		t.Fatal(err)
+	}
+	return &r
+}
+
+func TestReport_AsDocument(t *testing.T) {
+	r := decodeReport(t, `{
+		"id": "r1",
+		"name": "document",
+		"result": "consider",
+		"breakdown": {
+			"data_comparison": {"result": "clear", "breakdown": {"first_name": {"result": "clear", "properties": {}}}},
+			"visual_authenticity": {"result": "consider", "breakdown": {
+				"digital_tampering": {"result": "consider", "properties": {}},
+				"fonts": {"result": "clear", "properties": {}}
+			}},
+			"age_validation": {"result": "clear", "breakdown": {"minimum_accepted_age": {"result": "clear", "properties": {}}}},
+			"some_future_breakdown": {"result": "clear"}
+		},
+		"properties": {
+			"document_type": "passport",
+			"issuing_country": "GBR",
+			"date_of_expiry": "2031-01-01",
+			"nationality": "GBR",
+			"document_numbers": [{"type": "document_number", "value": "123456789"}]
+		}
+	}`)
+
+	d, err := r.AsDocument()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "r1", d.ID)
+	assert.Equal(t, onfido.ReportResultConsider, d.Result)
+	assert.Equal(t, onfido.DocumentTypePassport, d.Properties.DocumentType)
+	assert.Equal(t, "2031-01-01", d.Properties.DateOfExpiry)
+	assert.Equal(t, "GBR", d.Properties.Nationality)
+	assert.Equal(t, []onfido.DocumentNumber{{Type: "document_number", Value: "123456789"}}, d.Properties.DocumentNumbers)
+
+	if assert.NotNil(t, d.Breakdown.VisualAuthenticity) {
+		assert.Equal(t, onfido.BreakdownConsider, *d.Breakdown.VisualAuthenticity.Result)
+		assert.Equal(t, onfido.SubBreakdownConsider, *d.Breakdown.VisualAuthenticity.SubBreakdowns.DigitalTampering.Result)
+		assert.Equal(t, onfido.SubBreakdownClear, *d.Breakdown.VisualAuthenticity.SubBreakdowns.Fonts.Result)
+		assert.Nil(t, d.Breakdown.VisualAuthenticity.SubBreakdowns.Template)
+	}
+	assert.NotNil(t, d.Breakdown.AgeValidation.SubBreakdowns.MinimumAcceptedAge)
+	assert.Nil(t, d.Breakdown.ImageIntegrity)
+
+	// the raw breakdown and properties remain available
+	assert.Contains(t, d.Report.Breakdown, "some_future_breakdown")
+	assert.Equal(t, "GBR", d.Report.Properties["issuing_country"])
+}
+
+func TestReport_AsFacialSimilarity(t *testing.T) {
+	r := decodeReport(t, `{
+		"name": "facial_similarity_motion",
+		"breakdown": {
+			"face_comparison": {"result": "clear", "breakdown": {"face_match": {"result": "clear", "properties": {"score": 0.82}}}},
+			"visual_authenticity": {"result": "clear", "breakdown": {"spoofing_detection": {"result": "clear", "properties": {"score": 0.93}}}}
+		},
+		"properties": {"score": 0.82}
+	}`)
+
+	f, err := r.AsFacialSimilarity()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, 0.82, *f.Properties.Score)
+	assert.Equal(t, 0.82, f.Breakdown.FaceComparison.SubBreakdowns.FaceMatch.Properties["score"])
+	assert.Equal(t, onfido.SubBreakdownClear, *f.Breakdown.VisualAuthenticity.SubBreakdowns.SpoofingDetection.Result)
+}
+
+func TestReport_AsWatchlist(t *testing.T) {
+	r := decodeReport(t, `{
+		"name": "watchlist_standard",
+		"breakdown": {
+			"sanction": {"result": "consider"},
+			"politically_exposed_person": {"result": "clear"}
+		},
+		"properties": {"records": [{
+			"full_name": "John Smith",
+			"date_of_birth": ["1950-01-01"],
+			"sources": [{"source_name": "HM Treasury", "source_url": "https://example.com"}],
+			"aliases": [{"alias_name": "Johnny", "alias_type": "AKA"}]
+		}]}
+	}`)
+
+	w, err := r.AsWatchlist()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, onfido.BreakdownConsider, *w.Breakdown.Sanction.Result)
+	assert.Nil(t, w.Breakdown.AdverseMedia)
+	if assert.Len(t, w.Properties.Records, 1) {
+		rec := w.Properties.Records[0]
+		assert.Equal(t, "John Smith", rec.FullName)
+		assert.Equal(t, []string{"1950-01-01"}, rec.DateOfBirth)
+		assert.Equal(t, []onfido.WatchlistSource{{Name: "HM Treasury", URL: "https://example.com"}}, rec.Sources)
+		assert.Equal(t, []onfido.WatchlistAlias{{Name: "Johnny", Type: "AKA"}}, rec.Aliases)
+	}
+}
+
+func TestReport_AsProofOfAddress(t *testing.T) {
+	r := decodeReport(t, `{
+		"name": "proof_of_address",
+		"breakdown": {
+			"data_comparison": {"result": "clear", "breakdown": {"address": {"result": "clear"}}},
+			"image_integrity": {"result": "clear", "breakdown": {"image_quality": {"result": "clear"}}}
+		},
+		"properties": {"document_type": "utility_bill", "issuer": "Energy Co", "issue_date": "2024-01-05"}
+	}`)
+
+	p, err := r.AsProofOfAddress()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "utility_bill", p.Properties.DocumentType)
+	assert.Equal(t, "Energy Co", p.Properties.Issuer)
+	assert.Equal(t, onfido.SubBreakdownClear, *p.Breakdown.DataComparison.SubBreakdowns.Address.Result)
+	assert.Nil(t, p.Breakdown.DocumentClassification)
+}
+
+func TestReport_AsDeviceIntelligence(t *testing.T) {
+	r := decodeReport(t, `{
+		"name": "device_intelligence",
+		"breakdown": {
+			"device": {"result": "consider", "breakdown": {"device_integrity": {"result": "consider"}}}
+		},
+		"properties": {
+			"device": {"os": "iOS", "emulator": true, "fingerprint_reuse": 3},
+			"ip": {"address": "127.0.0.1", "ip_reputation": "HIGH_RISK"},
+			"geolocation": {"city": "London", "country": "GBR"}
+		}
+	}`)
+
+	d, err := r.AsDeviceIntelligence()
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, onfido.SubBreakdownConsider, *d.Breakdown.Device.SubBreakdowns.DeviceIntegrity.Result)
+	assert.True(t, d.Properties.Device.Emulator)
+	assert.Equal(t, 3, d.Properties.Device.FingerprintReuse)
+	assert.Equal(t, "HIGH_RISK", d.Properties.IP.IPReputation)
+	assert.Equal(t, "London", d.Properties.Geolocation.City)
+}
+
+func TestReport_UnexpectedName(t *testing.T) {
+	r := &onfido.Report{ID: "r1", Name: onfido.ReportNameWatchlistStandard}
+
+	_, err := r.AsDocument()
+	assert.True(t, errors.Is(err, onfido.ErrReportName))
+	assert.EqualError(t, err, "unexpected report name: watchlist_standard report r1 is not a document report")
+
+	_, err = r.AsWatchlist()
+	assert.NoError(t, err)
+}
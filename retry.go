@@ -0,0 +1,169 @@
+package onfido
+
+import (
+	"context"
+	"io"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy configures how the client retries failed requests.
+// A nil policy disables retries.
+//
+// Requests which aren't idempotent, e.g. creating an applicant or uploading a document,
+// are only retried when they were rejected with a 429, or a 503 with a Retry-After header,
+// as replaying them after a network error or a gateway error could create duplicates.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	MaxAttempts int
+	// BaseBackoff is the delay before the first retry, doubled on every subsequent retry.
+	BaseBackoff time.Duration
+	// MaxBackoff caps the computed delay between two attempts.
+	MaxBackoff time.Duration
+	// Jitter is the fraction (0 to 1) of each delay which is randomised.
+	Jitter float64
+	// RetryableStatuses lists the response status codes which are retried.
+	RetryableStatuses []int
+}
+
+// DefaultRetryPolicy returns a policy retrying rate limited and
+// transient server errors up to three times.
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 4,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+		Jitter:      0.2,
+		RetryableStatuses: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+func (p *RetryPolicy) retryableStatus(code int) bool {
+	for _, c := range p.RetryableStatuses {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// backoff returns the delay to wait before the given retry (1 being the first retry).
+func (p *RetryPolicy) backoff(retry int) time.Duration {
+	d := float64(p.BaseBackoff) * math.Pow(2, float64(retry-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// delay decides whether the attempt should be retried and how long to wait before doing so.
+func (p *RetryPolicy) delay(attempt int, req *http.Request, resp *http.Response, err error) (time.Duration, bool) {
+	if p == nil || attempt >= p.MaxAttempts || !canRewind(req) {
+		return 0, false
+	}
+	if err != nil {
+		// the request may have reached Onfido, so only replay it when doing so is harmless
+		return p.backoff(attempt), isIdempotent(req.Method)
+	}
+	if !p.retryableStatus(resp.StatusCode) {
+		return 0, false
+	}
+	retryAfter, hasRetryAfter := time.Duration(0), false
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		retryAfter, hasRetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+	}
+	// a gateway error may come after Onfido processed the request, so only replay
+	// non idempotent requests when the response shows they were turned down
+	if !isIdempotent(req.Method) && resp.StatusCode != http.StatusTooManyRequests &&
+		!(resp.StatusCode == http.StatusServiceUnavailable && hasRetryAfter) {
+		return 0, false
+	}
+	if hasRetryAfter {
+		return retryAfter, true
+	}
+	return p.backoff(attempt), true
+}
+
+// parseRetryAfter parses a Retry-After header in either of its
+// delay-seconds or HTTP-date forms.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		d := time.Until(t)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "", "GET", "HEAD", "OPTIONS", "PUT", "DELETE":
+		return true
+	}
+	return false
+}
+
+// canRewind reports whether the request body can be sent again.
+func canRewind(req *http.Request) bool {
+	return req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+}
+
+// rewind resets the request body so the request can be replayed.
+func rewind(req *http.Request) error {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return err
+	}
+	req.Body = body
+	return nil
+}
+
+// exceedsDeadline reports whether waiting for d would outlive the context deadline.
+func exceedsDeadline(ctx context.Context, d time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return ok && time.Until(deadline) < d
+}
+
+// sleep waits for d unless the context is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+func drain(resp *http.Response) {
+	if resp != nil && resp.Body != nil {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		resp.Body.Close()
+	}
+}
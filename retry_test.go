@@ -0,0 +1,237 @@
+package onfido
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testRetryPolicy() *RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.BaseBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+	return p
+}
+
+func TestDo_RetriesTransientErrors(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123"}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123")
+	client.Endpoint = srv.URL
+	client.RetryPolicy = testRetryPolicy()
+
+	a, err := client.GetApplicant(context.Background(), "123")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "123", a.ID)
+	assert.EqualValues(t, 3, atomic.LoadInt32(&attempts))
+}
+
+func TestDo_GivesUpAfterMaxAttempts(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123")
+	client.Endpoint = srv.URL
+	client.RetryPolicy = testRetryPolicy()
+
+	_, err := client.GetApplicant(context.Background(), "123")
+	onfidoErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected to see `*onfido.Error` but got %T", err)
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, onfidoErr.Resp.StatusCode)
+	assert.EqualValues(t, client.RetryPolicy.MaxAttempts, atomic.LoadInt32(&attempts))
+}
+
+func TestDo_NoRetryWithoutPolicy(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123")
+	client.Endpoint = srv.URL
+
+	_, err := client.GetApplicant(context.Background(), "123")
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&attempts))
+}
+
+func TestDo_NonRetryableStatus(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123")
+	client.Endpoint = srv.URL
+	client.RetryPolicy = testRetryPolicy()
+
+	_, err := client.GetApplicant(context.Background(), "123")
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&attempts))
+}
+
+func TestDo_ReplaysRequestBody(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		bodies = append(bodies, string(b))
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123"}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123")
+	client.Endpoint = srv.URL
+	client.RetryPolicy = testRetryPolicy()
+
+	_, err := client.UploadDocument(context.Background(), DocumentRequest{
+		File:        bytes.NewReader([]byte("\x89PNG\r\n\x1a\ntest")),
+		Type:        DocumentTypePassport,
+		ApplicantID: "123",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if assert.Len(t, bodies, 2) {
+		assert.NotEmpty(t, bodies[0])
+		assert.Equal(t, bodies[0], bodies[1])
+	}
+}
+
+func TestDo_RetryAfterBeyondDeadline(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123")
+	client.Endpoint = srv.URL
+	client.RetryPolicy = testRetryPolicy()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	_, err := client.GetApplicant(ctx, "123")
+	onfidoErr, ok := err.(*Error)
+	if !ok {
+		t.Fatalf("expected to see `*onfido.Error` but got %T", err)
+	}
+	assert.Equal(t, http.StatusTooManyRequests, onfidoErr.Resp.StatusCode)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&attempts))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	d, ok := parseRetryAfter("3")
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, d)
+
+	d, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.True(t, d > 59*time.Minute)
+
+	_, ok = parseRetryAfter("soon")
+	assert.False(t, ok)
+	_, ok = parseRetryAfter("")
+	assert.False(t, ok)
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	p := &RetryPolicy{BaseBackoff: time.Second, MaxBackoff: 5 * time.Second}
+	assert.Equal(t, time.Second, p.backoff(1))
+	assert.Equal(t, 2*time.Second, p.backoff(2))
+	assert.Equal(t, 4*time.Second, p.backoff(3))
+	assert.Equal(t, 5*time.Second, p.backoff(4))
+
+	p.Jitter = 0.5
+	for i := 0; i < 10; i++ {
+		d := p.backoff(1)
+		assert.True(t, d > 500*time.Millisecond && d <= time.Second)
+	}
+}
+
+func TestRetryPolicy_DelayNonIdempotent(t *testing.T) {
+	tests := []struct {
+		name       string
+		method     string
+		status     int
+		retryAfter string
+		retry      bool
+	}{
+		{"GET bad gateway", http.MethodGet, http.StatusBadGateway, "", true},
+		{"GET gateway timeout", http.MethodGet, http.StatusGatewayTimeout, "", true},
+		{"GET unavailable", http.MethodGet, http.StatusServiceUnavailable, "", true},
+		{"POST bad gateway", http.MethodPost, http.StatusBadGateway, "", false},
+		{"POST gateway timeout", http.MethodPost, http.StatusGatewayTimeout, "", false},
+		{"POST unavailable", http.MethodPost, http.StatusServiceUnavailable, "", false},
+		{"POST unavailable with Retry-After", http.MethodPost, http.StatusServiceUnavailable, "1", true},
+		{"POST too many requests", http.MethodPost, http.StatusTooManyRequests, "", true},
+	}
+
+	p := testRetryPolicy()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			resp := &http.Response{StatusCode: tt.status, Header: http.Header{}}
+			if tt.retryAfter != "" {
+				resp.Header.Set("Retry-After", tt.retryAfter)
+			}
+			_, retry := p.delay(1, req, resp, nil)
+			assert.Equal(t, tt.retry, retry)
+		})
+	}
+}
+
+func TestDo_NoReplayOfPostOnGatewayError(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusGatewayTimeout)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123")
+	client.Endpoint = srv.URL
+	client.RetryPolicy = testRetryPolicy()
+
+	_, err := client.CreateApplicant(context.Background(), Applicant{FirstName: "John"})
+	assert.Error(t, err)
+	assert.EqualValues(t, 1, atomic.LoadInt32(&attempts))
+}
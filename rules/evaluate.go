@@ -0,0 +1,198 @@
+package rules
+
+import (
+	"path"
+	"strings"
+
+	onfido "github.com/uw-labs/go-onfido"
+)
+
+// Outcome is the result of the evaluation of a check against a policy.
+type Outcome struct {
+	Decision Decision
+	// Result is the check result once the ignored breakdowns are disregarded.
+	Result onfido.CheckResult
+	// Fired lists the rules which fired, in the order of the policy.
+	Fired []Fired
+	// Ignored lists the breakdowns disregarded because of the policy's Ignore entries.
+	Ignored []onfido.ConsiderReason
+}
+
+// Fired is a rule which fired along with what made it fire.
+type Fired struct {
+	Rule Rule
+	// ReportIDs are the reports matching the rule, for rules with report level conditions.
+	ReportIDs []string
+	// Reasons are the breakdowns matching the rule's Breakdown pattern.
+	Reasons []onfido.ConsiderReason
+}
+
+// Evaluate decides on the check according to the policy.
+func (p *Policy) Evaluate(check *onfido.CheckExpanded) Outcome {
+	country := issuingCountry(check)
+
+	// the reasons of each report, without the ignored ones
+	reports := make([]evaluatedReport, 0, len(check.Reports))
+	var ignored []onfido.ConsiderReason
+	for _, r := range check.Reports {
+		if r == nil {
+			continue
+		}
+		e := evaluatedReport{report: r, result: r.Result, subResult: r.SubResult}
+		all := r.ConsiderReasons()
+		for _, reason := range all {
+			if p.ignored(reason, country) {
+				ignored = append(ignored, reason)
+				continue
+			}
+			e.reasons = append(e.reasons, reason)
+		}
+		if len(all) > 0 && len(e.reasons) == 0 && r.Result == onfido.ReportResultConsider {
+			e.result = onfido.ReportResultClear
+			if e.subResult != "" {
+				e.subResult = onfido.ReportSubResultClear
+			}
+		}
+		reports = append(reports, e)
+	}
+
+	out := Outcome{Decision: p.Default, Result: checkResult(check, reports), Ignored: ignored}
+	if out.Decision == "" {
+		out.Decision = Approve
+	}
+	var decided Decision
+	for _, rule := range p.Rules {
+		fired, ok := rule.evaluate(check, out.Result, country, reports)
+		if !ok {
+			continue
+		}
+		out.Fired = append(out.Fired, fired)
+		if rule.Decision.severity() > decided.severity() {
+			decided = rule.Decision
+		}
+	}
+	if decided != "" {
+		out.Decision = decided
+	}
+	return out
+}
+
+type evaluatedReport struct {
+	report *onfido.Report
+	// result and subResult are the report ones, clear if all its reasons are ignored
+	result    onfido.ReportResult
+	subResult onfido.ReportSubResult
+	reasons   []onfido.ConsiderReason
+}
+
+// checkResult returns the result of the check once the ignored breakdowns are disregarded,
+// clear if it was only considered because of reports which are now clear.
+func checkResult(check *onfido.CheckExpanded, reports []evaluatedReport) onfido.CheckResult {
+	if check.Result != onfido.CheckResultConsider || len(reports) == 0 {
+		return check.Result
+	}
+	for _, e := range reports {
+		if e.result != onfido.ReportResultClear {
+			return check.Result
+		}
+	}
+	return onfido.CheckResultClear
+}
+
+func (r Rule) evaluate(check *onfido.CheckExpanded, result onfido.CheckResult, country string, reports []evaluatedReport) (Fired, bool) {
+	c := r.When
+	fired := Fired{Rule: r}
+	if c.CheckStatus != "" && c.CheckStatus != check.Status {
+		return fired, false
+	}
+	if c.CheckResult != "" && c.CheckResult != result {
+		return fired, false
+	}
+	if len(c.Countries) > 0 && !containsFold(c.Countries, country) {
+		return fired, false
+	}
+	if !c.reportLevel() {
+		return fired, true
+	}
+
+	for _, e := range reports {
+		reasons, ok := c.matchReport(e)
+		if !ok {
+			continue
+		}
+		fired.ReportIDs = append(fired.ReportIDs, e.report.ID)
+		fired.Reasons = append(fired.Reasons, reasons...)
+	}
+	return fired, len(fired.ReportIDs) > 0
+}
+
+// matchReport reports whether the report level conditions match the report, along with the matching reasons.
+func (c Condition) matchReport(e evaluatedReport) ([]onfido.ConsiderReason, bool) {
+	if c.Report != "" && !match(c.Report, string(e.report.Name)) {
+		return nil, false
+	}
+	if c.Result != "" && c.Result != e.result {
+		return nil, false
+	}
+	if c.SubResult != "" && c.SubResult != e.subResult {
+		return nil, false
+	}
+	if c.Breakdown == "" {
+		return nil, true
+	}
+
+	var reasons []onfido.ConsiderReason
+	for _, reason := range e.reasons {
+		if match(c.Breakdown, reason.Path) {
+			reasons = append(reasons, reason)
+		}
+	}
+	return reasons, len(reasons) > 0
+}
+
+func (p *Policy) ignored(reason onfido.ConsiderReason, country string) bool {
+	for _, ig := range p.Ignore {
+		if ig.Report != "" && !match(ig.Report, string(reason.ReportName)) {
+			continue
+		}
+		if len(ig.Countries) > 0 && !containsFold(ig.Countries, country) {
+			continue
+		}
+		if match(ig.Breakdown, reason.Path) {
+			return true
+		}
+	}
+	return false
+}
+
+// issuingCountry returns the issuing country of the first document report of the check which has one.
+func issuingCountry(check *onfido.CheckExpanded) string {
+	for _, r := range check.Reports {
+		if r == nil {
+			continue
+		}
+		d, err := r.AsDocument()
+		if err == nil && d.Properties.IssuingCountry != "" {
+			return d.Properties.IssuingCountry
+		}
+	}
+	return ""
+}
+
+// match reports whether name matches the pattern, patterns being validated when the policy is loaded.
+func match(pattern, name string) bool {
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+func containsFold(values []string, s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,184 @@
+// Package rules turns the results of Onfido checks into approve, refer or reject decisions
+// according to a declarative policy, which can be loaded from YAML or JSON:
+//
+//	default: approve
+//	rules:
+//	  - name: rejected document
+//	    decision: reject
+//	    when:
+//	      report: document*
+//	      sub_result: rejected
+//	  - name: watchlist hit
+//	    decision: refer
+//	    when:
+//	      report: watchlist_*
+//	      result: consider
+//	ignore:
+//	  - report: document
+//	    breakdown: data_validation.mrz
+//	    countries: [FRA]
+//
+// A rule fires when all the conditions of its `when` clause hold. The decision is the most
+// severe of the fired rules, or the policy default when none fires.
+package rules
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	onfido "github.com/uw-labs/go-onfido"
+	"gopkg.in/yaml.v3"
+)
+
+// Decision is the outcome of the evaluation of a check
+type Decision string
+
+// Supported decisions, from the least to the most severe
+const (
+	Approve Decision = "approve"
+	Refer   Decision = "refer"
+	Reject  Decision = "reject"
+)
+
+func (d Decision) severity() int {
+	switch d {
+	case Approve:
+		return 1
+	case Refer:
+		return 2
+	case Reject:
+		return 3
+	}
+	return 0
+}
+
+// Policy is a set of rules deciding on checks.
+type Policy struct {
+	// Default is the decision when no rule fires, Approve if empty.
+	Default Decision `json:"default,omitempty" yaml:"default,omitempty"`
+	Rules   []Rule   `json:"rules" yaml:"rules"`
+	// Ignore lists the breakdowns which are disregarded, see Ignore.
+	Ignore []Ignore `json:"ignore,omitempty" yaml:"ignore,omitempty"`
+}
+
+// Rule makes a decision on the checks matching its condition.
+type Rule struct {
+	Name     string    `json:"name" yaml:"name"`
+	Decision Decision  `json:"decision" yaml:"decision"`
+	When     Condition `json:"when" yaml:"when"`
+}
+
+// Condition describes the checks a rule applies to, all its non empty fields must match.
+// The report level fields must all match the same report.
+type Condition struct {
+	CheckStatus onfido.CheckStatus `json:"check_status,omitempty" yaml:"check_status,omitempty"`
+	CheckResult onfido.CheckResult `json:"check_result,omitempty" yaml:"check_result,omitempty"`
+	// Countries matches the checks whose document was issued by one of the countries, as ISO 3166-1 alpha-3 codes.
+	Countries []string `json:"countries,omitempty" yaml:"countries,omitempty"`
+
+	// Report is a pattern matching report names, e.g. `watchlist_*`, see path.Match for the syntax.
+	Report    string                 `json:"report,omitempty" yaml:"report,omitempty"`
+	Result    onfido.ReportResult    `json:"result,omitempty" yaml:"result,omitempty"`
+	SubResult onfido.ReportSubResult `json:"sub_result,omitempty" yaml:"sub_result,omitempty"`
+	// Breakdown is a pattern matching the path of a breakdown which isn't clear, e.g. `visual_authenticity.*`.
+	Breakdown string `json:"breakdown,omitempty" yaml:"breakdown,omitempty"`
+}
+
+func (c Condition) reportLevel() bool {
+	return c.Report != "" || c.Result != "" || c.SubResult != "" || c.Breakdown != ""
+}
+
+func (c Condition) empty() bool {
+	return !c.reportLevel() && c.CheckStatus == "" && c.CheckResult == "" && len(c.Countries) == 0
+}
+
+// Ignore disregards the breakdowns matching it. A report whose result is only
+// explained by ignored breakdowns is considered clear, along with its sub result,
+// as is the check result when all its reports are clear.
+type Ignore struct {
+	// Report is a pattern matching report names, all reports if empty.
+	Report string `json:"report,omitempty" yaml:"report,omitempty"`
+	// Breakdown is a pattern matching breakdown paths, e.g. `data_validation.mrz`.
+	Breakdown string `json:"breakdown" yaml:"breakdown"`
+	// Countries restricts the ignore to the checks whose document was issued by one of the countries.
+	Countries []string `json:"countries,omitempty" yaml:"countries,omitempty"`
+}
+
+// ParseYAML parses and validates a YAML policy, rejecting unknown fields.
+func ParseYAML(data []byte) (*Policy, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	var p Policy
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("parsing policy: %w", err)
+	}
+	return &p, p.Validate()
+}
+
+// ParseJSON parses and validates a JSON policy, rejecting unknown fields.
+func ParseJSON(data []byte) (*Policy, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	var p Policy
+	if err := dec.Decode(&p); err != nil {
+		return nil, fmt.Errorf("parsing policy: %w", err)
+	}
+	return &p, p.Validate()
+}
+
+// LoadFile loads a policy from a JSON file if its extension is `.json`, from a YAML file otherwise.
+func LoadFile(name string) (*Policy, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(name), ".json") {
+		return ParseJSON(data)
+	}
+	return ParseYAML(data)
+}
+
+// Validate reports the first invalid decision, empty condition or malformed pattern of the policy.
+func (p *Policy) Validate() error {
+	if p.Default != "" && p.Default.severity() == 0 {
+		return fmt.Errorf("invalid default decision `%s`", p.Default)
+	}
+	for i, r := range p.Rules {
+		name := r.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if r.Decision.severity() == 0 {
+			return fmt.Errorf("rule %s: invalid decision `%s`", name, r.Decision)
+		}
+		if r.When.empty() {
+			return fmt.Errorf("rule %s: empty condition", name)
+		}
+		if err := validPatterns(r.When.Report, r.When.Breakdown); err != nil {
+			return fmt.Errorf("rule %s: %w", name, err)
+		}
+	}
+	for i, ig := range p.Ignore {
+		if ig.Breakdown == "" {
+			return fmt.Errorf("ignore #%d: missing breakdown", i+1)
+		}
+		if err := validPatterns(ig.Report, ig.Breakdown); err != nil {
+			return fmt.Errorf("ignore #%d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func validPatterns(patterns ...string) error {
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return fmt.Errorf("invalid pattern `%s`: %w", pattern, err)
+		}
+	}
+	return nil
+}
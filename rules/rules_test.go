@@ -0,0 +1,373 @@
+package rules_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	onfido "github.com/uw-labs/go-onfido"
+	"github.com/uw-labs/go-onfido/rules"
+)
+
+const yamlPolicy = `
+default: approve
+rules:
+  - name: rejected document
+    decision: reject
+    when:
+      report: document*
+      sub_result: rejected
+  - name: tampered document
+    decision: reject
+    when:
+      report: document
+      breakdown: visual_authenticity.*
+  - name: watchlist hit
+    decision: refer
+    when:
+      report: watchlist_*
+      result: consider
+  - name: consider check
+    decision: refer
+    when:
+      check_result: consider
+  - name: sanctioned country
+    decision: reject
+    when:
+      countries: [PRK]
+ignore:
+  - report: document
+    breakdown: data_validation.mrz
+    countries: [FRA]
+  - breakdown: image_integrity.colour_picture
+`
+
+const jsonPolicy = `{
+	"default": "approve",
+	"rules": [
+		{"name": "rejected document", "decision": "reject", "when": {"report": "document*", "sub_result": "rejected"}},
+		{"name": "tampered document", "decision": "reject", "when": {"report": "document", "breakdown": "visual_authenticity.*"}},
+		{"name": "watchlist hit", "decision": "refer", "when": {"report": "watchlist_*", "result": "consider"}},
+		{"name": "consider check", "decision": "refer", "when": {"check_result": "consider"}},
+		{"name": "sanctioned country", "decision": "reject", "when": {"countries": ["PRK"]}}
+	],
+	"ignore": [
+		{"report": "document", "breakdown": "data_validation.mrz", "countries": ["FRA"]},
+		{"breakdown": "image_integrity.colour_picture"}
+	]
+}`
+
+func documentReport(country, result, subResult, breakdown string) string {
+	return `{
+		"id": "doc",
+		"name": "document",
+		"result": "` + result + `",
+		"sub_result": "` + subResult + `",
+		"breakdown": ` + breakdown + `,
+		"properties": {"issuing_country": "` + country + `"}
+	}`
+}
+
+const clearBreakdown = `{"data_validation": {"result": "clear", "breakdown": {"mrz": {"result": "clear", "properties": {}}}}}`
+
+func watchlistReport(result string) string {
+	return `{
+		"id": "watch",
+		"name": "watchlist_standard",
+		"result": "` + result + `",
+		"breakdown": {"sanction": {"result": "` + result + `"}}
+	}`
+}
+
+func check(t *testing.T, result string, reports ...string) *onfido.CheckExpanded {
+	data := `{"id": "check", "status": "complete", "result": "` + result + `", "reports": [`
+	for i, r := range reports {
+		if i > 0 {
+			data += ","
+		}
+		data += r
+	}
+	data += `]}`
+
+	var c onfido.CheckExpanded
+	if err := json.Unmarshal([]byte(data), &c); err != nil {
+		t.Fatal(err)
+	}
+	return &c
+}
+
+func firedNames(out rules.Outcome) []string {
+	var names []string
+	for _, f := range out.Fired {
+		names = append(names, f.Rule.Name)
+	}
+	return names
+}
+
+func ignoredPaths(out rules.Outcome) []string {
+	var paths []string
+	for _, r := range out.Ignored {
+		paths = append(paths, r.Path)
+	}
+	return paths
+}
+
+func TestPolicy_Evaluate(t *testing.T) {
+	yamlP, err := rules.ParseYAML([]byte(yamlPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	jsonP, err := rules.ParseJSON([]byte(jsonPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, yamlP, jsonP)
+
+	tests := []struct {
+		name     string
+		check    *onfido.CheckExpanded
+		decision rules.Decision
+		fired    []string
+		ignored  []string
+	}{
+		{
+			name:     "clear check",
+			check:    check(t, "clear", documentReport("GBR", "clear", "clear", clearBreakdown), watchlistReport("clear")),
+			decision: rules.Approve,
+		},
+		{
+			name: "rejected document",
+			check: check(t, "consider",
+				documentReport("GBR", "consider", "rejected", `{"police_record": {"result": "consider"}}`),
+				watchlistReport("clear"),
+			),
+			decision: rules.Reject,
+			fired:    []string{"rejected document", "consider check"},
+		},
+		{
+			name: "tampered document",
+			check: check(t, "consider",
+				documentReport("GBR", "consider", "suspected", `{"visual_authenticity": {"result": "consider", "breakdown": {
+					"digital_tampering": {"result": "consider", "properties": {}},
+					"fonts": {"result": "clear", "properties": {}}
+				}}}`),
+			),
+			decision: rules.Reject,
+			fired:    []string{"tampered document", "consider check"},
+		},
+		{
+			name:     "watchlist hit",
+			check:    check(t, "consider", documentReport("GBR", "clear", "clear", clearBreakdown), watchlistReport("consider")),
+			decision: rules.Refer,
+			fired:    []string{"watchlist hit", "consider check"},
+		},
+		{
+			name: "ignored breakdown for country",
+			check: check(t, "consider",
+				documentReport("FRA", "consider", "caution", `{"data_validation": {"result": "consider", "breakdown": {"mrz": {"result": "consider", "properties": {}}}}}`),
+			),
+			decision: rules.Approve,
+			ignored:  []string{"data_validation.mrz"},
+		},
+		{
+			name: "ignored breakdown for other country",
+			check: check(t, "consider",
+				documentReport("GBR", "consider", "caution", `{"data_validation": {"result": "consider", "breakdown": {"mrz": {"result": "consider", "properties": {}}}}}`),
+			),
+			decision: rules.Refer,
+			fired:    []string{"consider check"},
+		},
+		{
+			name: "ignored breakdown clears sub result",
+			check: check(t, "consider",
+				documentReport("FRA", "consider", "rejected", `{"data_validation": {"result": "consider", "breakdown": {"mrz": {"result": "consider", "properties": {}}}}}`),
+				watchlistReport("clear"),
+			),
+			decision: rules.Approve,
+			ignored:  []string{"data_validation.mrz"},
+		},
+		{
+			name: "ignored breakdown clears report",
+			check: check(t, "consider",
+				documentReport("GBR", "consider", "caution", `{"image_integrity": {"result": "consider", "breakdown": {"colour_picture": {"result": "consider", "properties": {}}}}}`),
+				watchlistReport("clear"),
+			),
+			decision: rules.Approve,
+			ignored:  []string{"image_integrity.colour_picture"},
+		},
+		{
+			name: "ignore doesn't hide other breakdowns",
+			check: check(t, "consider",
+				documentReport("FRA", "consider", "suspected", `{
+					"data_validation": {"result": "consider", "breakdown": {"mrz": {"result": "consider", "properties": {}}}},
+					"visual_authenticity": {"result": "consider", "breakdown": {"fonts": {"result": "consider", "properties": {}}}}
+				}`),
+			),
+			decision: rules.Reject,
+			fired:    []string{"tampered document", "consider check"},
+			ignored:  []string{"data_validation.mrz"},
+		},
+		{
+			name:     "country condition",
+			check:    check(t, "clear", documentReport("PRK", "clear", "clear", clearBreakdown)),
+			decision: rules.Reject,
+			fired:    []string{"sanctioned country"},
+		},
+		{
+			name:     "no reports",
+			check:    check(t, "clear"),
+			decision: rules.Approve,
+		},
+	}
+
+	for _, p := range []struct {
+		name   string
+		policy *rules.Policy
+	}{{"yaml", yamlP}, {"json", jsonP}} {
+		for _, tt := range tests {
+			t.Run(p.name+"/"+tt.name, func(t *testing.T) {
+				out := p.policy.Evaluate(tt.check)
+				assert.Equal(t, tt.decision, out.Decision)
+				assert.Equal(t, tt.fired, firedNames(out))
+				assert.Equal(t, tt.ignored, ignoredPaths(out))
+			})
+		}
+	}
+}
+
+func TestPolicy_Evaluate_Fired(t *testing.T) {
+	p, err := rules.ParseYAML([]byte(yamlPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out := p.Evaluate(check(t, "consider",
+		documentReport("GBR", "consider", "suspected", `{"visual_authenticity": {"result": "consider", "breakdown": {
+			"digital_tampering": {"result": "consider", "properties": {}},
+			"template": {"result": "consider", "properties": {}}
+		}}}`),
+	))
+	if assert.Len(t, out.Fired, 2) {
+		f := out.Fired[0]
+		assert.Equal(t, rules.Reject, f.Rule.Decision)
+		assert.Equal(t, []string{"doc"}, f.ReportIDs)
+		if assert.Len(t, f.Reasons, 2) {
+			assert.Equal(t, "visual_authenticity.digital_tampering", f.Reasons[0].Path)
+			assert.Equal(t, "visual_authenticity.template", f.Reasons[1].Path)
+		}
+
+		f = out.Fired[1]
+		assert.Equal(t, "consider check", f.Rule.Name)
+		assert.Empty(t, f.ReportIDs)
+		assert.Empty(t, f.Reasons)
+	}
+}
+
+func TestPolicy_Evaluate_Result(t *testing.T) {
+	p, err := rules.ParseYAML([]byte(yamlPolicy))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mrz := `{"data_validation": {"result": "consider", "breakdown": {"mrz": {"result": "consider", "properties": {}}}}}`
+	assert.Equal(t, onfido.CheckResultClear, p.Evaluate(check(t, "consider", documentReport("FRA", "consider", "caution", mrz))).Result)
+	assert.Equal(t, onfido.CheckResultConsider, p.Evaluate(check(t, "consider", documentReport("GBR", "consider", "caution", mrz))).Result)
+	assert.Equal(t, onfido.CheckResultConsider, p.Evaluate(check(t, "consider",
+		documentReport("FRA", "consider", "caution", mrz),
+		watchlistReport("consider"),
+	)).Result)
+}
+
+func TestPolicy_Evaluate_Default(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   string
+		decision rules.Decision
+	}{
+		{"empty default", `rules: []`, rules.Approve},
+		{"refer default", `default: refer`, rules.Refer},
+		{"fired rule overrides default", `
+default: refer
+rules:
+  - name: clear
+    decision: approve
+    when: {check_result: clear}
+`, rules.Approve},
+		{"most severe rule wins", `
+rules:
+  - {name: a, decision: refer, when: {check_status: complete}}
+  - {name: b, decision: reject, when: {check_result: clear}}
+  - {name: c, decision: approve, when: {check_result: clear}}
+`, rules.Reject},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := rules.ParseYAML([]byte(tt.policy))
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, tt.decision, p.Evaluate(check(t, "clear")).Decision)
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		err    string
+	}{
+		{"unknown field", `rules: [{name: a, decision: reject, when: {reports: document}}]`, "field reports not found"},
+		{"invalid default", `default: maybe`, "invalid default decision `maybe`"},
+		{"invalid decision", `rules: [{name: a, decision: deny, when: {report: document}}]`, "rule a: invalid decision `deny`"},
+		{"missing decision", `rules: [{when: {report: document}}]`, "rule #1: invalid decision ``"},
+		{"empty condition", `rules: [{name: a, decision: reject}]`, "rule a: empty condition"},
+		{"invalid pattern", `rules: [{name: a, decision: reject, when: {report: "document["}}]`, "rule a: invalid pattern `document[`"},
+		{"missing ignore breakdown", `ignore: [{report: document}]`, "ignore #1: missing breakdown"},
+		{"invalid ignore pattern", `ignore: [{breakdown: "[a-"}]`, "ignore #1: invalid pattern `[a-`"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := rules.ParseYAML([]byte(tt.policy))
+			if assert.Error(t, err) {
+				assert.Contains(t, err.Error(), tt.err)
+			}
+		})
+	}
+
+	_, err := rules.ParseJSON([]byte(`{"rules": [], "unknown": true}`))
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), `unknown field "unknown"`)
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"policy.yaml": yamlPolicy,
+		"policy.yml":  yamlPolicy,
+		"policy.json": jsonPolicy,
+	}
+
+	var policies []*rules.Policy
+	for name, data := range files {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		p, err := rules.LoadFile(path)
+		if assert.NoError(t, err, name) {
+			policies = append(policies, p)
+		}
+	}
+	for _, p := range policies[1:] {
+		assert.Equal(t, policies[0], p)
+	}
+
+	_, err := rules.LoadFile(filepath.Join(dir, "missing.yaml"))
+	assert.True(t, os.IsNotExist(err))
+}
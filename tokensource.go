@@ -0,0 +1,179 @@
+package onfido
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TokenSource supplies the API token, it is consulted by the client for every request
+// so that tokens can be rotated without recreating the client.
+type TokenSource interface {
+	Token() (Token, error)
+}
+
+// TokenRefresher is implemented by token sources holding on to a token, such as
+// a cache. The client calls Refresh when the API rejects a token as unauthorized,
+// before asking the source for a token again and retrying the request once.
+type TokenRefresher interface {
+	Refresh()
+}
+
+// WithTokenSource makes the client get its token from src instead of the static Token field.
+func WithTokenSource(src TokenSource) Option {
+	return func(c *Client) {
+		c.TokenSource = src
+	}
+}
+
+type staticTokenSource struct {
+	token Token
+}
+
+// StaticTokenSource returns a TokenSource always returning t.
+func StaticTokenSource(t Token) TokenSource {
+	return staticTokenSource{token: t}
+}
+
+func (s staticTokenSource) Token() (Token, error) {
+	return s.token, nil
+}
+
+// Format prints the redacted token, fmt doesn't call the methods of unexported fields.
+func (s staticTokenSource) Format(f fmt.State, verb rune) {
+	s.token.Format(f, verb)
+}
+
+type envTokenSource string
+
+// EnvTokenSource returns a TokenSource reading the token from
+// the environment variable name every time it is asked for one.
+func EnvTokenSource(name string) TokenSource {
+	return envTokenSource(name)
+}
+
+func (s envTokenSource) Token() (Token, error) {
+	token := os.Getenv(string(s))
+	if token == "" {
+		return "", fmt.Errorf("onfido token not found in environmental variable `%s`", string(s))
+	}
+	return Token(token), nil
+}
+
+// FileTokenSource is a TokenSource reading the token from a file, such as one
+// mounted by a secrets manager. The file is read again whenever its size or
+// modification time changes, surrounding whitespace is ignored.
+type FileTokenSource struct {
+	path string
+
+	mu      sync.Mutex
+	token   Token
+	modTime time.Time
+	size    int64
+}
+
+// NewFileTokenSource creates a FileTokenSource for the file at path.
+func NewFileTokenSource(path string) *FileTokenSource {
+	return &FileTokenSource{path: path}
+}
+
+// Token returns the token held in the file, reading it again if it changed.
+func (s *FileTokenSource) Token() (Token, error) {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return "", err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && info.ModTime().Equal(s.modTime) && info.Size() == s.size {
+		return s.token, nil
+	}
+
+	b, err := os.ReadFile(s.path)
+	if err != nil {
+		return "", err
+	}
+	token := strings.TrimSpace(string(b))
+	if token == "" {
+		return "", fmt.Errorf("onfido token file `%s` is empty", s.path)
+	}
+	s.token, s.modTime, s.size = Token(token), info.ModTime(), info.Size()
+	return s.token, nil
+}
+
+// Refresh forces the file to be read again on the next call to Token.
+func (s *FileTokenSource) Refresh() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = ""
+}
+
+// Format prints the path of the file, never the token read from it.
+func (s *FileTokenSource) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "FileTokenSource(%s)", s.path)
+}
+
+// LogValue implements slog.LogValuer, logging the path of the file only.
+func (s *FileTokenSource) LogValue() slog.Value {
+	return slog.GroupValue(slog.String("path", s.path))
+}
+
+// CachingTokenSource is a TokenSource caching the token of another source,
+// typically one calling out to a secrets manager, for a fixed duration.
+type CachingTokenSource struct {
+	src TokenSource
+	ttl time.Duration
+
+	mu      sync.Mutex
+	token   Token
+	expires time.Time
+}
+
+// NewCachingTokenSource creates a CachingTokenSource keeping the tokens of src for ttl.
+func NewCachingTokenSource(src TokenSource, ttl time.Duration) *CachingTokenSource {
+	return &CachingTokenSource{src: src, ttl: ttl}
+}
+
+// Token returns the cached token, asking the underlying source for one if it expired.
+func (s *CachingTokenSource) Token() (Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.token != "" && time.Now().Before(s.expires) {
+		return s.token, nil
+	}
+
+	token, err := s.src.Token()
+	if err != nil {
+		return "", err
+	}
+	s.token, s.expires = token, time.Now().Add(s.ttl)
+	return token, nil
+}
+
+// Refresh discards the cached token, refreshing the underlying source too if it supports it.
+func (s *CachingTokenSource) Refresh() {
+	s.mu.Lock()
+	s.token = ""
+	s.mu.Unlock()
+
+	if r, ok := s.src.(TokenRefresher); ok {
+		r.Refresh()
+	}
+}
+
+// Format prints the type of the underlying source and the TTL, never the cached token.
+func (s *CachingTokenSource) Format(f fmt.State, verb rune) {
+	fmt.Fprintf(f, "CachingTokenSource(%T, %s)", s.src, s.ttl)
+}
+
+// LogValue implements slog.LogValuer, logging the type of the underlying source and the TTL.
+func (s *CachingTokenSource) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("source", fmt.Sprintf("%T", s.src)),
+		slog.Duration("ttl", s.ttl),
+	)
+}
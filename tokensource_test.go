@@ -0,0 +1,196 @@
+package onfido
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStaticTokenSource(t *testing.T) {
+	token, err := StaticTokenSource("abc").Token()
+	assert.NoError(t, err)
+	assert.Equal(t, Token("abc"), token)
+}
+
+func TestStaticTokenSource_Redacted(t *testing.T) {
+	client := NewClient("", WithTokenSource(StaticTokenSource("api_live.OTHERSECRETwxyz")))
+
+	for _, out := range []string{
+		fmt.Sprintf("%v", *client),
+		fmt.Sprintf("%+v", *client),
+		fmt.Sprintf("%#v", *client),
+		fmt.Sprintf("%s", client.TokenSource),
+	} {
+		assert.NotContains(t, out, "OTHERSECRET")
+	}
+}
+
+func TestTokenSources_Redacted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(path, []byte("api_live.FILESECRETwxyz"), 0600))
+	file := NewFileTokenSource(path)
+	caching := NewCachingTokenSource(StaticTokenSource("api_live.CACHEDSECRETwxyz"), time.Hour)
+	for _, src := range []TokenSource{file, caching} {
+		_, err := src.Token()
+		assert.NoError(t, err)
+	}
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, nil))
+	for _, src := range []TokenSource{file, caching} {
+		client := NewClient("", WithTokenSource(src))
+		for _, out := range []string{
+			fmt.Sprintf("%v", src),
+			fmt.Sprintf("%+v", src),
+			fmt.Sprintf("%#v", src),
+			fmt.Sprintf("%+v", *client),
+		} {
+			assert.NotContains(t, out, "SECRET")
+		}
+		logger.Info("source", "src", src)
+	}
+	assert.NotContains(t, buf.String(), "SECRET")
+	assert.Contains(t, buf.String(), path)
+}
+
+func TestEnvTokenSource(t *testing.T) {
+	src := EnvTokenSource("ONFIDO_TEST_TOKEN")
+	_, err := src.Token()
+	assert.Error(t, err)
+
+	os.Setenv("ONFIDO_TEST_TOKEN", "abc")
+	defer os.Unsetenv("ONFIDO_TEST_TOKEN")
+	token, err := src.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, Token("abc"), token)
+}
+
+func TestFileTokenSource(t *testing.T) {
+	dir, err := os.MkdirTemp("", "onfido")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "token")
+
+	src := NewFileTokenSource(path)
+	_, err = src.Token()
+	assert.Error(t, err)
+
+	assert.NoError(t, os.WriteFile(path, []byte("first\n"), 0600))
+	token, err := src.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, Token("first"), token)
+
+	assert.NoError(t, os.WriteFile(path, []byte("second-token\n"), 0600))
+	token, err = src.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, Token("second-token"), token)
+}
+
+func TestCachingTokenSource(t *testing.T) {
+	calls := 0
+	src := NewCachingTokenSource(tokenSourceFunc(func() (Token, error) {
+		calls++
+		return Token("token"), nil
+	}), time.Hour)
+
+	for i := 0; i < 3; i++ {
+		token, err := src.Token()
+		assert.NoError(t, err)
+		assert.Equal(t, Token("token"), token)
+	}
+	assert.Equal(t, 1, calls)
+
+	src.Refresh()
+	_, err := src.Token()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestNewRequest_TokenSource(t *testing.T) {
+	client := NewClient("", WithTokenSource(StaticTokenSource("from-source")))
+	req, err := client.newRequest("GET", "/foo", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "Token token=from-source", req.Header.Get("Authorization"))
+
+	expected := errors.New("secrets manager unavailable")
+	client.TokenSource = tokenSourceFunc(func() (Token, error) { return "", expected })
+	_, err = client.newRequest("GET", "/foo", nil)
+	assert.Equal(t, expected, err)
+}
+
+func TestDo_RefreshesRejectedToken(t *testing.T) {
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+		if r.Header.Get("Authorization") != "Token token=rotated" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123"}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	current := Token("revoked")
+	src := NewCachingTokenSource(tokenSourceFunc(func() (Token, error) {
+		return current, nil
+	}), time.Hour)
+	client := NewClient("", WithBaseURL(srv.URL), WithTokenSource(src))
+
+	_, err := client.GetApplicant(context.Background(), "123")
+	assert.True(t, errors.Is(err, ErrAuthorization))
+	assert.Equal(t, []string{"Token token=revoked"}, seen)
+
+	current = "rotated"
+	seen = nil
+	a, err := client.CreateApplicant(context.Background(), Applicant{FirstName: "Rob"})
+	assert.NoError(t, err)
+	assert.Equal(t, "123", a.ID)
+	assert.Equal(t, []string{"Token token=revoked", "Token token=rotated"}, seen)
+}
+
+func TestDo_RefreshedTokenEnvironmentMismatch(t *testing.T) {
+	var seen []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = append(seen, r.Header.Get("Authorization"))
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	current := Token("api_sandbox.revoked")
+	src := NewCachingTokenSource(tokenSourceFunc(func() (Token, error) {
+		return current, nil
+	}), time.Hour)
+	client := NewClient("", WithBaseURL(srv.URL), WithTokenSource(src), WithEnvironment(EnvironmentSandbox))
+
+	_, err := client.GetApplicant(context.Background(), "123")
+	assert.True(t, errors.Is(err, ErrAuthorization))
+
+	// the rotated token is for the live environment so it is never sent
+	current = "api_live.rotated"
+	seen = nil
+	_, err = client.GetApplicant(context.Background(), "123")
+	assert.True(t, errors.Is(err, ErrAuthorization))
+	assert.Equal(t, []string{"Token token=api_sandbox.revoked"}, seen)
+}
+
+type tokenSourceFunc func() (Token, error)
+
+func (f tokenSourceFunc) Token() (Token, error) {
+	return f()
+}
@@ -0,0 +1,354 @@
+package onfido
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// sniffLen is the number of bytes http.DetectContentType looks at.
+const sniffLen = 512
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
+
+// formField is a plain form-data field sent along with an uploaded file.
+type formField struct {
+	name  string
+	value string
+}
+
+// formFile describes the file of a multipart upload.
+type formFile struct {
+	fieldname   string
+	filename    string
+	contentType string
+	file        io.ReadSeeker
+	start       int64
+	size        int64
+}
+
+// newFormFile reads the file details needed to upload it from its current position.
+// When not provided, the file name is taken from *os.File readers and the content type
+// is sniffed from the first 512 bytes of the file. The file is left at its starting position.
+func newFormFile(fieldname string, file io.ReadSeeker, filename, contentType string) (*formFile, error) {
+	if file == nil {
+		return nil, &FileError{Field: fieldname, Err: ErrEmptyFile}
+	}
+	start, err := file.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	end, err := file.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	if filename == "" {
+		if f, ok := file.(*os.File); ok {
+			filename = filepath.Base(f.Name())
+		}
+	}
+
+	if contentType == "" {
+		buffer := make([]byte, sniffLen)
+		n, err := io.ReadFull(file, buffer)
+		if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+			return nil, err
+		}
+		if _, err := file.Seek(start, io.SeekStart); err != nil {
+			return nil, err
+		}
+		contentType = detectContentType(buffer[:n], filename)
+	}
+
+	return &formFile{
+		fieldname:   fieldname,
+		filename:    filename,
+		contentType: contentType,
+		file:        file,
+		start:       start,
+		size:        end - start,
+	}, nil
+}
+
+// heifBrands maps the ISO base media file brands of HEIC and HEIF images to their content type.
+var heifBrands = map[string]string{
+	"heic": "image/heic",
+	"heix": "image/heic",
+	"heim": "image/heic",
+	"heis": "image/heic",
+	"hevc": "image/heic-sequence",
+	"hevx": "image/heic-sequence",
+	"mif1": "image/heif",
+	"msf1": "image/heif-sequence",
+}
+
+// detectContentType sniffs the content type of data, the start of a file, recognising HEIC
+// images which http.DetectContentType doesn't. It falls back to the file name extension.
+func detectContentType(data []byte, filename string) string {
+	contentType := http.DetectContentType(data)
+	if contentType != "application/octet-stream" {
+		return contentType
+	}
+	// an ftyp box, its size followed by `ftyp` and the major brand
+	if len(data) >= 12 && string(data[4:8]) == "ftyp" {
+		if t, ok := heifBrands[string(data[8:12])]; ok {
+			return t
+		}
+	}
+	ext := filepath.Ext(filename)
+	if strings.EqualFold(ext, ".heic") {
+		return "image/heic"
+	}
+	if t := mime.TypeByExtension(ext); t != "" {
+		return t
+	}
+	return contentType
+}
+
+// Upload validation errors, wrapped in a *FileError.
+var (
+	// ErrEmptyFile means no file or an empty one was given for an upload
+	ErrEmptyFile = errors.New("empty file")
+	// ErrFileTooLarge means the file exceeds the maximum size Onfido accepts
+	ErrFileTooLarge = errors.New("file too large")
+	// ErrUnsupportedFileType means the file is in a format Onfido doesn't accept
+	ErrUnsupportedFileType = errors.New("unsupported file type")
+)
+
+// FileError reports a file rejected before being uploaded. It wraps
+// ErrEmptyFile, ErrFileTooLarge or ErrUnsupportedFileType, and matches
+// ErrValidation like the errors Onfido returns for invalid uploads.
+type FileError struct {
+	// Field is the form field of the file, e.g. `file`
+	Field       string
+	FileName    string
+	ContentType string
+	Size        int64
+	Err         error
+}
+
+func (e *FileError) Error() string {
+	name := e.FileName
+	if name == "" {
+		name = e.Field
+	}
+	switch e.Err {
+	case ErrFileTooLarge:
+		return fmt.Sprintf("%s: %v (%d bytes)", name, e.Err, e.Size)
+	case ErrUnsupportedFileType:
+		return fmt.Sprintf("%s: %v `%s`", name, e.Err, e.ContentType)
+	}
+	return fmt.Sprintf("%s: %v", name, e.Err)
+}
+
+// Unwrap returns the reason the file was rejected.
+func (e *FileError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrValidation.
+func (e *FileError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// fileFormat describes the files accepted by an upload endpoint.
+type fileFormat struct {
+	contentTypes []string
+	maxSize      int64
+}
+
+// validate rejects files which don't match the format before they are sent.
+func (f *formFile) validate(format fileFormat) error {
+	fileErr := func(err error) error {
+		return &FileError{
+			Field:       f.fieldname,
+			FileName:    f.filename,
+			ContentType: f.contentType,
+			Size:        f.size,
+			Err:         err,
+		}
+	}
+
+	if f.size <= 0 {
+		return fileErr(ErrEmptyFile)
+	}
+	if format.maxSize > 0 && f.size > format.maxSize {
+		return fileErr(ErrFileTooLarge)
+	}
+	mediaType, _, err := mime.ParseMediaType(f.contentType)
+	if err != nil {
+		return fileErr(ErrUnsupportedFileType)
+	}
+	for _, t := range format.contentTypes {
+		if mediaType == t {
+			return nil
+		}
+	}
+	return fileErr(ErrUnsupportedFileType)
+}
+
+// createFormFile creates a new form-data header with the field name,
+// file name, and file content type of f.
+// this is used instead of multipart.Writer.CreateFormFile because Onfido API
+// doesn't accept 'application/octet-stream' as content-type.
+func createFormFile(writer *multipart.Writer, f *formFile) (io.Writer, error) {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition",
+		fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(f.fieldname), escapeQuotes(f.filename)))
+	h.Set("Content-Type", f.contentType)
+
+	return writer.CreatePart(h)
+}
+
+// multipartBody streams a multipart form through a pipe instead of building it in memory.
+// It can be opened again to retry a request, the file being read anew from its starting position.
+type multipartBody struct {
+	boundary string
+	file     *formFile
+	fields   []formField
+
+	mu   sync.Mutex
+	pr   *io.PipeReader
+	done chan struct{}
+}
+
+func newMultipartBody(file *formFile, fields []formField) *multipartBody {
+	return &multipartBody{
+		boundary: multipart.NewWriter(io.Discard).Boundary(),
+		file:     file,
+		fields:   fields,
+	}
+}
+
+// write writes the multipart form to w, leaving out the file content unless withFile is set.
+func (b *multipartBody) write(w io.Writer, withFile bool) error {
+	writer := multipart.NewWriter(w)
+	if err := writer.SetBoundary(b.boundary); err != nil {
+		return err
+	}
+
+	part, err := createFormFile(writer, b.file)
+	if err != nil {
+		return err
+	}
+	if withFile {
+		n, err := io.Copy(part, b.file.file)
+		if err != nil {
+			return err
+		}
+		if n != b.file.size {
+			return fmt.Errorf("%s changed while being uploaded", b.file.fieldname)
+		}
+	}
+	for _, f := range b.fields {
+		if err := writer.WriteField(f.name, f.value); err != nil {
+			return err
+		}
+	}
+	return writer.Close()
+}
+
+// contentLength returns the size of the multipart form.
+func (b *multipartBody) contentLength() (int64, error) {
+	var cw countingWriter
+	if err := b.write(&cw, false); err != nil {
+		return 0, err
+	}
+	return int64(cw) + b.file.size, nil
+}
+
+func (b *multipartBody) contentType() string {
+	return "multipart/form-data; boundary=" + b.boundary
+}
+
+// open starts writing the multipart form in the background, returning the reading end of the pipe.
+// The writing of a previously opened body is stopped first, so that it no longer reads the file.
+func (b *multipartBody) open() (io.ReadCloser, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stop()
+	if _, err := b.file.file.Seek(b.file.start, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		pw.CloseWithError(b.write(pw, true))
+	}()
+	b.pr, b.done = pr, done
+	return pr, nil
+}
+
+// close stops the writing of the last opened body.
+func (b *multipartBody) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.stop()
+}
+
+func (b *multipartBody) stop() {
+	if b.pr != nil {
+		b.pr.Close()
+		<-b.done
+		b.pr = nil
+	}
+}
+
+// upload POSTs a multipart form with the file and fields, decoding the response into v.
+// The form is streamed with a known Content-Length rather than built in memory,
+// and is written anew from the file when the request is retried.
+func (c *Client) upload(ctx context.Context, uri string, file *formFile, fields []formField, v interface{}) error {
+	req, err := c.newRequest("POST", uri, nil)
+	if err != nil {
+		return err
+	}
+
+	body := newMultipartBody(file, fields)
+	length, err := body.contentLength()
+	if err != nil {
+		return err
+	}
+	rc, err := body.open()
+	if err != nil {
+		return err
+	}
+	// the body may not be consumed, e.g. when the context is done before the request is sent
+	defer body.close()
+
+	req.Body = rc
+	req.GetBody = body.open
+	req.ContentLength = length
+	req.Header.Set("Content-Type", body.contentType())
+
+	_, err = c.do(ctx, req, v)
+	return err
+}
+
+type countingWriter int64
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	*w += countingWriter(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,217 @@
+package onfido
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n")
+
+func TestUploadDocument_StreamsMultipartForm(t *testing.T) {
+	content := append(append([]byte{}, pngHeader...), bytes.Repeat([]byte("x"), 100000)...)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, []string(nil), r.TransferEncoding)
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, r.ContentLength, int64(len(body)))
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		form := readForm(t, r)
+		assert.Equal(t, "passport", string(form["type"].data))
+		assert.Equal(t, "front", string(form["side"].data))
+		assert.Equal(t, "applicant-id", string(form["applicant_id"].data))
+		assert.Equal(t, "image/png", form["file"].header.Get("Content-Type"))
+		assert.Equal(t, content, form["file"].data)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123"}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123", WithBaseURL(srv.URL))
+	d, err := client.UploadDocument(context.Background(), DocumentRequest{
+		File:        bytes.NewReader(content),
+		Type:        DocumentTypePassport,
+		Side:        DocumentSideFront,
+		ApplicantID: "applicant-id",
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "123", d.ID)
+}
+
+func TestUploadDocument_Retried(t *testing.T) {
+	content := append(append([]byte{}, pngHeader...), "image"...)
+	var received [][]byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, readForm(t, r)["file"].data)
+		if len(received) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123"}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.BaseBackoff = time.Millisecond
+	client := NewClient("123", WithBaseURL(srv.URL), WithRetryPolicy(policy))
+
+	_, err := client.UploadDocument(context.Background(), DocumentRequest{File: bytes.NewReader(content)})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{content, content}, received)
+}
+
+func TestUpload_NotSent(t *testing.T) {
+	client := NewClient("123", WithBaseURL("http://127.0.0.1:0"))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client.RateLimiter = NewRateLimiter(1, 1)
+
+	// the body written in the background is stopped even though the request is never sent
+	_, err := client.UploadDocument(ctx, DocumentRequest{File: bytes.NewReader(pngHeader)})
+	assert.Equal(t, context.Canceled, err)
+}
+
+func TestMultipartBody_ContentLength(t *testing.T) {
+	file, err := newFormFile("file", bytes.NewReader([]byte("some text")), "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, "text/plain; charset=utf-8", file.contentType)
+
+	body := newMultipartBody(file, []formField{{"type", "passport"}})
+	length, err := body.contentLength()
+	assert.NoError(t, err)
+
+	rc, err := body.open()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(rc)
+	assert.NoError(t, err)
+	assert.Equal(t, length, int64(len(data)))
+	body.close()
+}
+
+type formPart struct {
+	header textproto.MIMEHeader
+	data   []byte
+}
+
+// readForm reads the parts of a multipart request by name, the file parts
+// of the uploads not necessarily having a file name.
+func readForm(t *testing.T, r *http.Request) map[string]formPart {
+	form := make(map[string]formPart)
+	mr, err := r.MultipartReader()
+	if !assert.NoError(t, err) {
+		return form
+	}
+	for {
+		p, err := mr.NextPart()
+		if err == io.EOF || !assert.NoError(t, err) {
+			return form
+		}
+		data, err := io.ReadAll(p)
+		assert.NoError(t, err)
+		form[p.FormName()] = formPart{header: p.Header, data: data}
+	}
+}
+
+func TestDetectContentType(t *testing.T) {
+	heic := append([]byte("\x00\x00\x00\x18ftypheic"), make([]byte, 20)...)
+	for _, tc := range []struct {
+		name     string
+		data     []byte
+		filename string
+		expected string
+	}{
+		{"png", pngHeader, "", "image/png"},
+		{"jpeg", []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), "", "image/jpeg"},
+		{"pdf", []byte("%PDF-1.7\n"), "", "application/pdf"},
+		{"heic", heic, "", "image/heic"},
+		{"heic extension", []byte{0, 1, 2, 3}, "IMG_0001.HEIC", "image/heic"},
+		{"unknown", []byte{0, 1, 2, 3}, "", "application/octet-stream"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, detectContentType(tc.data, tc.filename))
+		})
+	}
+}
+
+func TestFormFile_Validate(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		file     formFile
+		expected error
+	}{
+		{"valid", formFile{contentType: "image/png", size: 100}, nil},
+		{"content type parameters", formFile{contentType: "application/pdf; charset=binary", size: 100}, nil},
+		{"empty", formFile{contentType: "image/png"}, ErrEmptyFile},
+		{"too large", formFile{contentType: "image/png", size: MaxDocumentSize + 1}, ErrFileTooLarge},
+		{"unsupported", formFile{contentType: "text/plain; charset=utf-8", size: 100}, ErrUnsupportedFileType},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.file.validate(documentFormat)
+			if tc.expected == nil {
+				assert.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, tc.expected))
+			assert.True(t, errors.Is(err, ErrValidation))
+			var fileErr *FileError
+			assert.True(t, errors.As(err, &fileErr))
+		})
+	}
+}
+
+func TestUploadDocument_FileNameAndContentType(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		part := readForm(t, r)["file"]
+		assert.Equal(t, `form-data; name="file"; filename="scan.pdf"`, part.header.Get("Content-Disposition"))
+		assert.Equal(t, "application/pdf", part.header.Get("Content-Type"))
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"123"}`))
+		assert.NoError(t, wErr)
+	}))
+	defer srv.Close()
+
+	client := NewClient("123", WithBaseURL(srv.URL))
+	_, err := client.UploadDocument(context.Background(), DocumentRequest{
+		File:        bytes.NewReader([]byte("binary content not sniffed as a PDF")),
+		FileName:    "scan.pdf",
+		ContentType: "application/pdf",
+	})
+	assert.NoError(t, err)
+}
+
+func TestUploadDocument_RejectedBeforeSending(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("unexpected request")
+	}))
+	defer srv.Close()
+
+	client := NewClient("123", WithBaseURL(srv.URL))
+	_, err := client.UploadDocument(context.Background(), DocumentRequest{
+		File:     bytes.NewReader([]byte("plain text")),
+		FileName: "notes.txt",
+	})
+	assert.True(t, errors.Is(err, ErrUnsupportedFileType))
+	assert.True(t, errors.Is(err, ErrValidation))
+	assert.EqualError(t, err, "notes.txt: unsupported file type `text/plain; charset=utf-8`")
+
+	_, err = client.UploadDocument(context.Background(), DocumentRequest{})
+	assert.True(t, errors.Is(err, ErrEmptyFile))
+}
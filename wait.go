@@ -0,0 +1,131 @@
+package onfido
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Default polling intervals of WaitForCheck
+const (
+	DefaultWaitInterval    = 2 * time.Second
+	DefaultWaitMaxInterval = time.Minute
+)
+
+// ErrCheckNotComplete means WaitForCheck stopped on a status configured with WaitFail.
+var ErrCheckNotComplete = errors.New("check not complete")
+
+// WaitPolicy tells WaitForCheck how to handle a check status which isn't final.
+type WaitPolicy int
+
+// Supported wait policies
+const (
+	// WaitContinue keeps polling the check until its status changes.
+	WaitContinue WaitPolicy = iota
+	// WaitReturn stops polling and returns the check as it is.
+	WaitReturn
+	// WaitFail stops polling and returns the check along with ErrCheckNotComplete.
+	WaitFail
+)
+
+// WaitOption configures WaitForCheck.
+type WaitOption func(*waitConfig)
+
+type waitConfig struct {
+	interval    time.Duration
+	maxInterval time.Duration
+	policies    map[CheckStatus]WaitPolicy
+	onChange    func(previous CheckStatus, check *Check)
+	expand      []ExpandOption
+}
+
+// WaitInterval sets the delay before polling the check again, doubled after
+// every poll up to max. It defaults to DefaultWaitInterval and DefaultWaitMaxInterval,
+// an interval which isn't positive being replaced by DefaultWaitInterval.
+func WaitInterval(interval, max time.Duration) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.interval = interval
+		cfg.maxInterval = max
+	}
+}
+
+// WaitOnStatus sets how the awaiting_applicant, paused and reopened statuses are handled,
+// all of them defaulting to WaitContinue. The in_progress status is always waited on,
+// the complete and withdrawn statuses always end the wait.
+func WaitOnStatus(status CheckStatus, policy WaitPolicy) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.policies[status] = policy
+	}
+}
+
+// OnStatusChange registers a callback invoked every time the polled check is seen
+// with a new status, the first time with an empty previous status.
+func OnStatusChange(fn func(previous CheckStatus, check *Check)) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.onChange = fn
+	}
+}
+
+// WaitExpand sets how the reports of the check are fetched once the wait is over, see GetCheckExpanded.
+func WaitExpand(opts ...ExpandOption) WaitOption {
+	return func(cfg *waitConfig) {
+		cfg.expand = append(cfg.expand, opts...)
+	}
+}
+
+// WaitForCheck polls a check by its ID with an exponential backoff until it is complete
+// or withdrawn, and returns it with its reports expanded. It is meant as a fallback
+// for when the check completion webhooks can't be received.
+// The wait is bounded by the context, and can be cut short on other statuses with WaitOnStatus.
+func (c *Client) WaitForCheck(ctx context.Context, id string, opts ...WaitOption) (*CheckExpanded, error) {
+	cfg := waitConfig{
+		interval:    DefaultWaitInterval,
+		maxInterval: DefaultWaitMaxInterval,
+		policies:    make(map[CheckStatus]WaitPolicy),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	if cfg.interval <= 0 {
+		cfg.interval = DefaultWaitInterval
+	}
+
+	var previous CheckStatus
+	interval := cfg.interval
+	for {
+		check, err := c.GetCheck(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if check.Status != previous && cfg.onChange != nil {
+			cfg.onChange(previous, check)
+		}
+		previous = check.Status
+
+		switch cfg.policy(check.Status) {
+		case WaitReturn:
+			return c.expandCheck(ctx, check, cfg.expand)
+		case WaitFail:
+			return &CheckExpanded{Check: *check}, fmt.Errorf("%w: check %s is %s", ErrCheckNotComplete, id, check.Status)
+		}
+
+		if err := sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+		interval *= 2
+		if cfg.maxInterval > 0 && interval > cfg.maxInterval {
+			interval = cfg.maxInterval
+		}
+	}
+}
+
+func (cfg *waitConfig) policy(status CheckStatus) WaitPolicy {
+	switch status {
+	case CheckStatusComplete, CheckStatusWithdrawn:
+		return WaitReturn
+	case CheckStatusInProgress:
+		return WaitContinue
+	}
+	return cfg.policies[status]
+}
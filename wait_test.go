@@ -0,0 +1,120 @@
+package onfido_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+	onfido "github.com/uw-labs/go-onfido"
+)
+
+// newCheckServer serves a check going through the given statuses, one per poll,
+// the last status being repeated.
+func newCheckServer(t *testing.T, statuses ...onfido.CheckStatus) (*httptest.Server, *int) {
+	polls := 0
+	m := mux.NewRouter()
+	m.HandleFunc("/checks/{id}", func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[len(statuses)-1]
+		if polls < len(statuses) {
+			status = statuses[polls]
+		}
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"` + mux.Vars(r)["id"] + `","status":"` + string(status) + `","report_ids":["r1"]}`))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	m.HandleFunc("/reports/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, wErr := w.Write([]byte(`{"id":"` + mux.Vars(r)["id"] + `","name":"document","result":"clear"}`))
+		assert.NoError(t, wErr)
+	}).Methods("GET")
+	return httptest.NewServer(m), &polls
+}
+
+func TestWaitForCheck(t *testing.T) {
+	srv, polls := newCheckServer(t,
+		onfido.CheckStatusInProgress,
+		onfido.CheckStatusInProgress,
+		onfido.CheckStatusAwaitingApplicant,
+		onfido.CheckStatusComplete,
+	)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	type transition struct{ from, to onfido.CheckStatus }
+	var transitions []transition
+	check, err := client.WaitForCheck(context.Background(), "c1",
+		onfido.WaitInterval(time.Millisecond, 2*time.Millisecond),
+		onfido.OnStatusChange(func(previous onfido.CheckStatus, check *onfido.Check) {
+			transitions = append(transitions, transition{previous, check.Status})
+		}),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 4, *polls)
+	assert.Equal(t, onfido.CheckStatusComplete, check.Status)
+	if assert.Len(t, check.Reports, 1) {
+		assert.Equal(t, "r1", check.Reports[0].ID)
+	}
+	assert.Equal(t, []transition{
+		{"", onfido.CheckStatusInProgress},
+		{onfido.CheckStatusInProgress, onfido.CheckStatusAwaitingApplicant},
+		{onfido.CheckStatusAwaitingApplicant, onfido.CheckStatusComplete},
+	}, transitions)
+}
+
+func TestWaitForCheck_StatusPolicies(t *testing.T) {
+	srv, _ := newCheckServer(t, onfido.CheckStatusInProgress, onfido.CheckStatusPaused)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+	interval := onfido.WaitInterval(time.Millisecond, time.Millisecond)
+
+	check, err := client.WaitForCheck(context.Background(), "c1", interval,
+		onfido.WaitOnStatus(onfido.CheckStatusPaused, onfido.WaitReturn))
+	assert.NoError(t, err)
+	assert.Equal(t, onfido.CheckStatusPaused, check.Status)
+	assert.Len(t, check.Reports, 1)
+
+	check, err = client.WaitForCheck(context.Background(), "c1", interval,
+		onfido.WaitOnStatus(onfido.CheckStatusPaused, onfido.WaitFail))
+	assert.True(t, errors.Is(err, onfido.ErrCheckNotComplete))
+	assert.EqualError(t, err, "check not complete: check c1 is paused")
+	assert.Equal(t, onfido.CheckStatusPaused, check.Status)
+
+	// the policy of final and in progress statuses can't be changed
+	check, err = client.WaitForCheck(context.Background(), "c1", interval,
+		onfido.WaitOnStatus(onfido.CheckStatusInProgress, onfido.WaitFail),
+		onfido.WaitOnStatus(onfido.CheckStatusPaused, onfido.WaitReturn))
+	assert.NoError(t, err)
+	assert.Equal(t, onfido.CheckStatusPaused, check.Status)
+}
+
+func TestWaitForCheck_ContextDone(t *testing.T) {
+	srv, _ := newCheckServer(t, onfido.CheckStatusAwaitingApplicant)
+	defer srv.Close()
+	client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := client.WaitForCheck(ctx, "c1", onfido.WaitInterval(time.Millisecond, 5*time.Millisecond))
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
+
+func TestWaitForCheck_InvalidInterval(t *testing.T) {
+	for _, interval := range []time.Duration{0, -time.Second} {
+		srv, polls := newCheckServer(t, onfido.CheckStatusInProgress)
+		client := onfido.NewClient("123", onfido.WithBaseURL(srv.URL))
+
+		// the default interval is used instead of polling in a tight loop
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		_, err := client.WaitForCheck(ctx, "c1", onfido.WaitInterval(interval, 0))
+		cancel()
+		srv.Close()
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+		assert.Equal(t, 1, *polls)
+	}
+}
@@ -25,7 +25,7 @@ var (
 
 // Webhook represents a webhook handler
 type Webhook struct {
-	Token                   string
+	Token                   Token
 	SkipSignatureValidation bool
 }
 
@@ -57,13 +57,13 @@ func NewWebhookFromEnv() (*Webhook, error) {
 // NewWebhook creates a new webhook handler
 func NewWebhook(token string) *Webhook {
 	return &Webhook{
-		Token: token,
+		Token: Token(token),
 	}
 }
 
 // ValidateSignature validates the request body against the signature header.
 func (wh *Webhook) ValidateSignature(body []byte, signature string) error {
-	mac := hmac.New(sha1.New, []byte(wh.Token))
+	mac := hmac.New(sha1.New, []byte(wh.Token.Reveal()))
 	if _, err := mac.Write(body); err != nil {
 		return err
 	}
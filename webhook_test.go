@@ -29,7 +29,7 @@ func TestNewWebhookFromEnv_TokenSet(t *testing.T) {
 	if err != nil {
 		t.Fatal()
 	}
-	if wh.Token != expected {
+	if wh.Token.Reveal() != expected {
 		t.Fatalf("expected to see `%s` token but got `%s`", expected, wh.Token)
 	}
 }
@@ -45,7 +45,7 @@ type WebhookRef struct {
 	URL          string               `json:"url,omitempty"`
 	Enabled      bool                 `json:"enabled"`
 	Href         string               `json:"href,omitempty"`
-	Token        string               `json:"token,omitempty"`
+	Token        Token                `json:"token,omitempty"`
 	Environments []WebhookEnvironment `json:"environments,omitempty"`
 	Events       []WebhookEvent       `json:"events,omitempty"`
 }
@@ -69,39 +69,25 @@ func (c *Client) CreateWebhook(ctx context.Context, wr WebhookRefRequest) (*Webh
 	}
 
 	var resp WebhookRef
-	_, err = c.do(ctx, req, &resp)
+	_, err = c.do(withOperation(ctx, "CreateWebhook"), req, &resp)
 	return &resp, err
 }
 
-// WebhookRefIter represents a webhook iterator
+// WebhookRefIter represents a webhook iterator.
+// It only adds the deprecated WebhookRef accessor to Iter[*WebhookRef].
 type WebhookRefIter struct {
-	*iter
+	*Iter[*WebhookRef]
 }
 
 // WebhookRef returns the current item in the iterator as a WebhookRef.
+//
+// Deprecated: use Current.
 func (i *WebhookRefIter) WebhookRef() *WebhookRef {
-	return i.Current().(*WebhookRef)
+	return i.Current()
 }
 
 // ListWebhooks retrieves the list of webhooks.
 // see https://documentation.onfido.com/#list-webhooks
-func (c *Client) ListWebhooks() *WebhookRefIter {
-	handler := func(body []byte) ([]interface{}, error) {
-		var r WebhookRefs
-		if err := json.Unmarshal(body, &r); err != nil {
-			return nil, err
-		}
-
-		values := make([]interface{}, len(r.WebhookRefs))
-		for i, v := range r.WebhookRefs {
-			values[i] = v
-		}
-		return values, nil
-	}
-
-	return &WebhookRefIter{&iter{
-		c:       c,
-		nextURL: "/webhooks/",
-		handler: handler,
-	}}
+func (c *Client) ListWebhooks(opts ...ListOption) *WebhookRefIter {
+	return &WebhookRefIter{newIter[*WebhookRef](c, "ListWebhooks", "webhooks", listURL("/webhooks/", nil, opts))}
 }
@@ -1,6 +1,7 @@
 package onfido_test
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"net/http"
@@ -43,6 +44,7 @@ func TestCreateWebhook_WebhookCreated(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	expectedJSON = revealToken(expectedJSON, expected.Token)
 
 	m := mux.NewRouter()
 	m.HandleFunc("/webhooks", func(w http.ResponseWriter, r *http.Request) {
@@ -112,6 +114,7 @@ func TestListWebhooks_WebhooksRetrieved(t *testing.T) {
 	if err != nil {
 		t.Fatal(err)
 	}
+	expectedJSON = revealToken(expectedJSON, expected.Token)
 
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
@@ -140,3 +143,9 @@ func TestListWebhooks_WebhooksRetrieved(t *testing.T) {
 		t.Fatal(it.Err())
 	}
 }
+
+// revealToken replaces the redacted token in the JSON encoded webhook,
+// as the API responds with the token in clear.
+func revealToken(b []byte, token onfido.Token) []byte {
+	return bytes.Replace(b, []byte(token.String()), []byte(token.Reveal()), 1)
+}